@@ -120,15 +120,52 @@ func hasMoreThanSixDecimalPlaces(s string) bool {
     return false
 }
 
+type Translator func(key string, args map[string]interface{}) string
+
+var DefaultMessages = map[string]string{
+	"cryptobot.too_many_decimals": "Неккоректный ввод, слишком много цифр после запятой, попробуйте снова: {amount}",
+	"cryptobot.amount_entered":    "Вы ввели: {amount}",
+	"cryptobot.invalid_amount":    "Неккоректный ввод, попробуйте снова: {amount}",
+}
+
+func defaultTranslator(key string, args map[string]interface{}) string {
+	text, ok := DefaultMessages[key]
+	if !ok {
+		return key
+	}
+	for name, value := range args {
+		text = strings.ReplaceAll(text, "{"+name+"}", fmt.Sprint(value))
+	}
+	return text
+}
+
 func CheckNumber(data string) (bool, string){
+	return CheckNumberWith(data, defaultTranslator)
+}
+
+// CheckNumberWith falls back to DefaultMessages for keys that translate
+// returns unchanged, so a bundle without the cryptobot.* keys still works.
+func CheckNumberWith(data string, translate Translator) (bool, string) {
+	args := map[string]interface{}{"amount": data}
+	if translate == nil {
+		translate = defaultTranslator
+	} else {
+		custom := translate
+		translate = func(key string, args map[string]interface{}) string {
+			if text := custom(key, args); text != key {
+				return text
+			}
+			return defaultTranslator(key, args)
+		}
+	}
 	if isPositiveFloat(data) {
 		if hasMoreThanSixDecimalPlaces(data) {
-			return false, "Неккоректный ввод, слишком много цифр после запятой, попробуйте снова: " + data
+			return false, translate("cryptobot.too_many_decimals", args)
 		} else {
-			return true, "Вы ввели: " + data
+			return true, translate("cryptobot.amount_entered", args)
 		}
 	}
-	return false, "Неккоректный ввод, попробуйте снова: " + data
+	return false, translate("cryptobot.invalid_amount", args)
 }
 
 func NewCryptoBotApi(apiToken, proxyURL string) *CryptoBotApi {
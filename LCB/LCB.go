@@ -1,16 +1,16 @@
 package LCB
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"runtime/debug"
 	"sync"
-	"mime/multipart"
-	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -22,6 +22,28 @@ type Bot struct {
 	getText   map[int64]string
 	state 	 map[int64]map[string]interface{}
 	Mu sync.Mutex
+	stateMu sync.Mutex
+	AllowedUpdates []string
+	callbackCodec *CallbackCodec
+	throttler     *Throttler
+	commands      []Command
+	syncedCommands map[string]commandTarget
+	me            *User
+	meMu          sync.Mutex
+	bundle        *Bundle
+	templates     *TemplateRegistry
+	offsetStore   OffsetStore
+	offsetMu      sync.Mutex
+	updates       *updateTracker
+	AckTimeout      time.Duration
+	HandlerAttempts int
+	DedupWindow     int
+	HTTPClient      *http.Client
+	recorder        *Recorder
+	replaying       bool
+	callbackMu       sync.Mutex
+	autoAnswer       time.Duration
+	pendingCallbacks map[string]bool
 }
 
 type Handler struct {
@@ -34,6 +56,12 @@ type Update struct {
 	Message       *Message       `json:"message"`
 	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
 	InlineQuery   *InlineQuery   `json:"inline_query"`
+	ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result,omitempty"`
+	ChatJoinRequest    *ChatJoinRequest    `json:"chat_join_request,omitempty"`
+	MyChatMember       *ChatMemberUpdated  `json:"my_chat_member,omitempty"`
+	ChatMember         *ChatMemberUpdated  `json:"chat_member,omitempty"`
+	ShippingQuery      *ShippingQuery      `json:"shipping_query,omitempty"`
+	PreCheckoutQuery   *PreCheckoutQuery   `json:"pre_checkout_query,omitempty"`
 }
 
 type ResponsePostMessage struct {
@@ -49,6 +77,7 @@ type InlineQuery struct {
 	Query    string `json:"query"`
 	Offset   string `json:"offset"`
 	ChatType string `json:"chat_type"`
+	Location *Location `json:"location,omitempty"`
 }
 
 type Message struct {
@@ -58,6 +87,16 @@ type Message struct {
 	Chat        *Chat         `json:"chat"`
 	Photo       []PhotoSize   `json:"photo"`
 	Caption     string        `json:"caption"`
+	Entities    []MessageEntity `json:"entities"`
+	NewChatMembers []User       `json:"new_chat_members"`
+	MessageThreadID    int64               `json:"message_thread_id"`
+	IsTopicMessage     bool                `json:"is_topic_message"`
+	ForumTopicCreated  *ForumTopicCreated  `json:"forum_topic_created"`
+	ForumTopicEdited   *ForumTopicEdited   `json:"forum_topic_edited"`
+	ForumTopicClosed   *ForumTopicClosed   `json:"forum_topic_closed"`
+	ForumTopicReopened *ForumTopicReopened `json:"forum_topic_reopened"`
+	SuccessfulPayment  *SuccessfulPayment  `json:"successful_payment"`
+	RefundedPayment    *RefundedPayment    `json:"refunded_payment"`
 	ReplyMarkup *reply_markup `json:"reply_markup"`
 	Dice 		*Dice 		  `json:"dice"`
 }
@@ -89,6 +128,12 @@ type PhotoSize struct {
 
 type Chat struct {
 	ID int64 `json:"id"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	IsForum   bool   `json:"is_forum,omitempty"`
 }
 
 type CallbackQuery struct {
@@ -101,6 +146,10 @@ type CallbackQuery struct {
 type User struct {
 	ID       int64  `json:"id"`
 	Username string `json:"username"`
+	IsBot        bool   `json:"is_bot,omitempty"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
 }
 
 type TelegramResponse struct {
@@ -125,19 +174,31 @@ type InlineKeyboardMarkup struct {
 
 type InlineKeyboardButton struct {
 	Text         string      `json:"text"`
-	URL          string      `json:"url"`
-	CallbackData string      `json:"callback_data"`
-	WebApp       *WebAppInfo `json:"web_app"`
+	URL          string      `json:"url,omitempty"`
+	CallbackData string      `json:"callback_data,omitempty"`
+	WebApp       *WebAppInfo `json:"web_app,omitempty"`
+	SwitchInlineQuery            *string `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrentChat *string `json:"switch_inline_query_current_chat,omitempty"`
+	Pay                          bool    `json:"pay,omitempty"`
 }
 
 type ReplyKeyboardMarkup struct {
 	ReplyKeyboard [][]ReplyKeyboardButton `json:"keyboard"`
 	ResizeKeyboard  bool                  `json:"resize_keyboard"`
 	OneTimeKeyboard bool                  `json:"one_time_keyboard"`
+	IsPersistent          bool            `json:"is_persistent,omitempty"`
+	InputFieldPlaceholder string          `json:"input_field_placeholder,omitempty"`
+	Selective             bool            `json:"selective,omitempty"`
 }
 
 type ReplyKeyboardButton struct {
 	Text string                           `json:"text"`
+	RequestUsers    *KeyboardButtonRequestUsers `json:"request_users,omitempty"`
+	RequestChat     *KeyboardButtonRequestChat  `json:"request_chat,omitempty"`
+	RequestContact  bool                        `json:"request_contact,omitempty"`
+	RequestLocation bool                        `json:"request_location,omitempty"`
+	RequestPoll     *KeyboardButtonPollType     `json:"request_poll,omitempty"`
+	WebApp          *WebAppInfo                 `json:"web_app,omitempty"`
 }
 
 type WebAppInfo struct {
@@ -148,10 +209,103 @@ type Keyboards struct {
 	Inline *InlineKeyboardMarkup
 	Reply *ReplyKeyboardMarkup
 	Delete *DeleteKeyboard
+	ForceReply *ForceReply
 }
 
 type DeleteKeyboard struct {
 	Remove_keyboard bool            `json:"remove_keyboard"`
+	Selective       bool            `json:"selective,omitempty"`
+}
+
+type ForceReply struct {
+	ForceReply            bool   `json:"force_reply"`
+	InputFieldPlaceholder string `json:"input_field_placeholder,omitempty"`
+	Selective             bool   `json:"selective,omitempty"`
+}
+
+type SendOptions struct {
+	Entities            []MessageEntity
+	ReplyParameters     *ReplyParameters
+	MessageThreadID     int64
+	DisableNotification bool
+	ProtectContent      bool
+	LinkPreviewOptions  *LinkPreviewOptions
+	MessageEffectID     string
+}
+
+type ReplyParameters struct {
+	MessageID                int64  `json:"message_id"`
+	ChatID                   int64  `json:"chat_id,omitempty"`
+	AllowSendingWithoutReply bool   `json:"allow_sending_without_reply,omitempty"`
+	Quote                    string `json:"quote,omitempty"`
+	QuoteParseMode           string `json:"quote_parse_mode,omitempty"`
+	QuotePosition            int    `json:"quote_position,omitempty"`
+}
+
+type LinkPreviewOptions struct {
+	IsDisabled       bool   `json:"is_disabled,omitempty"`
+	URL              string `json:"url,omitempty"`
+	PreferSmallMedia bool   `json:"prefer_small_media,omitempty"`
+	PreferLargeMedia bool   `json:"prefer_large_media,omitempty"`
+	ShowAboveText    bool   `json:"show_above_text,omitempty"`
+}
+
+func sendOptions(opts []SendOptions) SendOptions {
+	if len(opts) == 0 {
+		return SendOptions{}
+	}
+	return opts[0]
+}
+
+// Telegram rejects parameters a method does not define, so each method passes
+// the list of options it accepts.
+var (
+	sendMessageOptions     = []string{"entities", "reply_parameters", "message_thread_id", "disable_notification", "protect_content", "link_preview_options", "message_effect_id"}
+	sendMediaOptions       = []string{"caption_entities", "reply_parameters", "message_thread_id", "disable_notification", "protect_content", "message_effect_id"}
+	sendDiceOptions        = []string{"reply_parameters", "message_thread_id", "disable_notification", "protect_content", "message_effect_id"}
+	sendInvoiceOptions     = sendDiceOptions
+	editMessageTextOptions = []string{"entities", "link_preview_options"}
+	forwardMessageOptions  = []string{"message_thread_id", "disable_notification", "protect_content"}
+	copyMessageOptions     = []string{"caption_entities", "reply_parameters", "message_thread_id", "disable_notification", "protect_content"}
+	copyMessagesOptions    = forwardMessageOptions
+)
+
+func (o SendOptions) apply(message map[string]interface{}, allowed []string) {
+	fields := map[string]interface{}{}
+	if len(o.Entities) > 0 {
+		fields["entities"] = o.Entities
+		fields["caption_entities"] = o.Entities
+	}
+	if o.ReplyParameters != nil {
+		fields["reply_parameters"] = o.ReplyParameters
+	}
+	if o.MessageThreadID != 0 {
+		fields["message_thread_id"] = o.MessageThreadID
+	}
+	if o.DisableNotification {
+		fields["disable_notification"] = true
+	}
+	if o.ProtectContent {
+		fields["protect_content"] = true
+	}
+	if o.LinkPreviewOptions != nil {
+		fields["link_preview_options"] = o.LinkPreviewOptions
+	}
+	if o.MessageEffectID != "" {
+		fields["message_effect_id"] = o.MessageEffectID
+	}
+	for _, key := range allowed {
+		if value, ok := fields[key]; ok {
+			message[key] = value
+		}
+	}
+}
+
+var defaultAllowedUpdates = []string{
+	"message", "edited_message", "channel_post", "edited_channel_post",
+	"inline_query", "chosen_inline_result", "callback_query",
+	"shipping_query", "pre_checkout_query", "poll", "poll_answer",
+	"my_chat_member", "chat_join_request",
 }
 
 type Filter interface {
@@ -168,6 +322,10 @@ type FilterCallback struct {
 	Callback string
 }
 
+type FilterCallbackPrefix struct {
+	Prefix string
+}
+
 type FilterDice struct {
 	Emoji string
 	Value int
@@ -197,6 +355,13 @@ func (f FilterCallback) Match(update Update) bool {
 	return update.CallbackQuery.Data == f.Callback
 }
 
+func (f FilterCallbackPrefix) Match(update Update) bool {
+	if update.CallbackQuery == nil || update.CallbackQuery.Data == "" {
+		return false
+	}
+	return strings.HasPrefix(update.CallbackQuery.Data, f.Prefix)
+}
+
 func (f FilterDice) Match(update Update) bool {
 	if update.Message == nil || update.Message.Dice == nil{
 		return false
@@ -219,11 +384,30 @@ func NewBot(token string) *Bot {
 	}
 }
 
+func (b *Bot) AllowUpdate(updateType string) {
+	if len(b.AllowedUpdates) == 0 {
+		b.AllowedUpdates = append([]string{}, defaultAllowedUpdates...)
+	}
+	for _, allowed := range b.AllowedUpdates {
+		if allowed == updateType {
+			return
+		}
+	}
+	b.AllowedUpdates = append(b.AllowedUpdates, updateType)
+}
+
 func (b *Bot) AddHandler(filter Filter, callback func(update Update)) {
+	if command, ok := filter.(FilterCommand); ok && command.bot == nil {
+		command.bot = b
+		filter = command
+	}
 	b.handlers = append(b.handlers, Handler{Filter: filter, Callback: callback})
 }
 
 func (b *Bot) SetState(userID int64, key string, data interface{}) {
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+
 	if b.state[userID] == nil {
 		b.state[userID] = make(map[string]interface{})
 	}
@@ -232,6 +416,9 @@ func (b *Bot) SetState(userID int64, key string, data interface{}) {
 }
 
 func (b *Bot) GetState(userID int64, key string) interface{} {
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+
 	if b.state == nil {
 		b.state = make(map[int64]map[string]interface{})
 	}
@@ -248,36 +435,74 @@ func (b *Bot) GetState(userID int64, key string) interface{} {
 }
 
 func (b *Bot) CleanState(userID int64) {
+	b.stateMu.Lock()
+	defer b.stateMu.Unlock()
+
 	b.state[userID] = nil
 }
 
 func (b *Bot) Start() {
+	if b.replaying {
+		log.Println("Not starting a bot that has been used for Replay")
+		return
+	}
+	b.syncCommandsOnStart()
+	b.cacheUsername()
+	b.lastUpdateId = b.loadOffset()
+	b.updates = newUpdateTracker(b.lastUpdateId, b.DedupWindow)
 	go b.pollUpdates()
-	go b.processUpdates()
+	go b.processUpdates(b.updatesChan)
 }
 
 func (b *Bot) pollUpdates() {
 	defer close(b.updatesChan)
+	var backoff time.Duration
 	for {
-		updates, err := b.getUpdates(b.lastUpdateId)
+		updates, err := b.getUpdates(b.commitOffset())
 		if err != nil {
-			log.Println("Error getting updates:", err)
+			backoff = nextPollBackoff(backoff)
+			log.Println("Error getting updates:", err, "retrying in", backoff)
+			time.Sleep(backoff)
 			continue
 		}
+		backoff = 0
 
+		fresh := 0
 		for _, update := range updates {
-			if b.lastUpdateId <= update.Update_id {
-				b.lastUpdateId = update.Update_id + 1
+			if !b.updates.dispatch(update.Update_id) {
+				continue
 			}
+			fresh++
 			b.updatesChan <- update
 		}
+		// everything returned is still being handled, don't spin on it
+		if len(updates) > 0 && fresh == 0 {
+			time.Sleep(pollIdleDelay)
+		}
 	}
 }
 
-func (b *Bot) processUpdates() {
+func (b *Bot) processUpdates(updatesChan <-chan Update) {
 	flag_stop := false
-	for update := range b.updatesChan {
+	for update := range updatesChan {
 		flag_stop = false
+		if b.callbackCodec != nil && update.CallbackQuery != nil {
+			data, ok := b.callbackCodec.Decode(update.CallbackQuery.Data)
+			if !ok && b.replaying {
+				log.Println("Replay: passing undecodable callback data to handlers:", update.CallbackQuery.Data)
+				data, ok = update.CallbackQuery.Data, true
+			}
+			if !ok {
+				b.dropInvalidCallback(update.CallbackQuery)
+				b.ackUpdate(update.Update_id)
+				continue
+			}
+			update.CallbackQuery.Data = data
+		}
+		if update.CallbackQuery != nil {
+			b.trackCallback(update.CallbackQuery)
+		}
+
 		if update.Message != nil && update.Message.Text != nil {
 			b.Mu.Lock()
 			for key, _ := range b.getText {
@@ -290,17 +515,106 @@ func (b *Bot) processUpdates() {
 			b.Mu.Unlock()
 		}
 
+		if !flag_stop && b.throttler != nil && !b.replaying {
+			wait, ok := b.throttler.admit(update)
+			if !ok {
+				b.ackUpdate(update.Update_id)
+				continue
+			}
+			if wait > 0 {
+				go func(update Update) {
+					time.Sleep(wait)
+					b.runHandlers(update)
+				}(update)
+				continue
+			}
+		}
+
 		if !flag_stop {
-			for _, handler := range b.handlers {
-				if handler.Filter == nil || handler.Callback == nil {
-					continue
-				}
-				if handler.Filter.Match(update) {
-					go handler.Callback(update)
-				}
+			b.runHandlers(update)
+		} else {
+			b.ackUpdate(update.Update_id)
+		}
+	}
+}
+
+func (b *Bot) runHandlers(update Update) {
+	var wg sync.WaitGroup
+	for _, handler := range b.handlers {
+		if handler.Filter == nil || handler.Callback == nil {
+			continue
+		}
+		if handler.Filter.Match(update) {
+			wg.Add(1)
+			go func(callback func(update Update)) {
+				defer wg.Done()
+				b.runHandler(update, callback)
+			}(handler.Callback)
+		}
+	}
+	go func() {
+		wg.Wait()
+		b.ackUpdate(update.Update_id)
+	}()
+}
+
+// runHandler calls one handler, retrying it alone when it panics and
+// HandlerAttempts allows, so handlers that succeeded are never repeated.
+func (b *Bot) runHandler(update Update, callback func(update Update)) {
+	attempts := b.HandlerAttempts
+	if attempts <= 0 {
+		attempts = defaultHandlerAttempts
+	}
+	for attempt := 1; !callHandler(update, callback); attempt++ {
+		if attempt >= attempts {
+			if attempts > 1 {
+				log.Println("Giving up on update", update.Update_id, "after", attempt, "attempts")
 			}
+			return
+		}
+		time.Sleep(handlerRetryDelay)
+	}
+}
+
+func callHandler(update Update, callback func(update Update)) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Handler panic on update %d: %v\n%s", update.Update_id, r, debug.Stack())
+			ok = false
 		}
+	}()
+	callback(update)
+	return true
+}
+
+func updateChatID(update Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat != nil:
+		return update.CallbackQuery.Message.Chat.ID
+	}
+	return 0
+}
+
+func updateUserID(update Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return update.Message.From.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		return update.CallbackQuery.From.ID
+	case update.InlineQuery != nil && update.InlineQuery.From != nil:
+		return update.InlineQuery.From.ID
+	case update.ChosenInlineResult != nil && update.ChosenInlineResult.From != nil:
+		return update.ChosenInlineResult.From.ID
+	case update.ChatJoinRequest != nil && update.ChatJoinRequest.From != nil:
+		return update.ChatJoinRequest.From.ID
+	case update.ShippingQuery != nil && update.ShippingQuery.From != nil:
+		return update.ShippingQuery.From.ID
+	case update.PreCheckoutQuery != nil && update.PreCheckoutQuery.From != nil:
+		return update.PreCheckoutQuery.From.ID
 	}
+	return 0
 }
 
 func (b *Bot) GetDataFromUser(userID int64) string {
@@ -324,133 +638,65 @@ func (b *Bot) GetDataFromUser(userID int64) string {
 	}
 }
 
-func (b *Bot) SendPhoto(chatID int64, photoPathOrFileID string, caption string, parseMode string, keyboards *Keyboards) int {
-    var requestBody io.Reader
-    var err error
-    var writer *multipart.Writer
+func (b *Bot) SendPhoto(chatID int64, photoPathOrFileID string, caption string, parseMode string, keyboards *Keyboards, opts ...SendOptions) int {
+	messageID, err := b.TrySendPhoto(chatID, photoPathOrFileID, caption, parseMode, keyboards, opts...)
+	if err != nil {
+		log.Println("Error sending photo:", err)
+		return 0
+	}
+	return messageID
+}
 
-    if isFileID(photoPathOrFileID) {
-        message := map[string]interface{}{
-            "chat_id": chatID,
-            "photo":   photoPathOrFileID,
-        }
+func (b *Bot) TrySendPhoto(chatID int64, photoPathOrFileID string, caption string, parseMode string, keyboards *Keyboards, opts ...SendOptions) (int, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
 
-        if caption != "" {
-            message["caption"] = caption
-        }
+	if caption != "" {
+		message["caption"] = caption
+	}
 
-		if parseMode != "" {
-			message["parse_mode"] = parseMode
-		}
+	if parseMode != "" {
+		message["parse_mode"] = parseMode
+	}
 
-        if keyboards != nil {
-            if keyboards.Reply != nil {
-                message["reply_markup"] = keyboards.Reply
-            }
-            if keyboards.Inline != nil {
-                message["reply_markup"] = keyboards.Inline
-            }
-        }
-
-        messageJSON, err := json.Marshal(message)
-        if err != nil {
-            log.Println("Error marshalling message:", err)
-            return 0
-        }
-        requestBody = bytes.NewBuffer(messageJSON)
-    } else {
-        file, err := os.Open(photoPathOrFileID)
-        if err != nil {
-            log.Println("Error opening file:", err)
-            return 0
-        }
-        defer file.Close()
-
-        var buffer bytes.Buffer
-        writer = multipart.NewWriter(&buffer)
-
-        photoPart, err := writer.CreateFormFile("photo", filepath.Base(photoPathOrFileID))
-        if err != nil {
-            log.Println("Error creating form file:", err)
-            return 0
-        }
-        _, err = io.Copy(photoPart, file)
-        if err != nil {
-            log.Println("Error copying file:", err)
-            return 0
-        }
-
-        err = writer.WriteField("chat_id", fmt.Sprintf("%d", chatID))
-        if err != nil {
-            log.Println("Error writing field:", err)
-            return 0
-        }
-        if caption != "" {
-            err = writer.WriteField("caption", caption)
-            if err != nil {
-                log.Println("Error writing caption:", err)
-                return 0
-            }
-        }
-
-        if keyboards != nil {
-            if keyboards.Reply != nil {
-                err = writer.WriteField("reply_markup", serializeKeyboard(keyboards.Reply))
-            } else if keyboards.Inline != nil {
-                err = writer.WriteField("reply_markup", serializeKeyboard(keyboards.Inline))
-            }
-            if err != nil {
-                log.Println("Error writing keyboard:", err)
-                return 0
-            }
-        }
-
-        writer.Close()
-        requestBody = &buffer
-    }
+	sendOptions(opts).apply(message, sendMediaOptions)
 
-    url := "https://api.telegram.org/bot" + b.Token + "/sendPhoto"
-    req, err := http.NewRequest("POST", url, requestBody)
-    if err != nil {
-        log.Println("Error creating request:", err)
-        return 0
-    }
+	markup, err := keyboards.ReplyMarkup()
+	if err != nil {
+		return 0, err
+	}
+	if markup != nil {
+		message["reply_markup"] = markup
+	}
 
-    if isFileID(photoPathOrFileID) {
-        req.Header.Set("Content-Type", "application/json")
-    } else {
-        req.Header.Set("Content-Type", writer.FormDataContentType())
-    }
+	var result MessageID
+	if isFileID(photoPathOrFileID) {
+		message["photo"] = photoPathOrFileID
+		err = b.callMethod("sendPhoto", message, &result)
+	} else {
+		err = b.callMultipart("sendPhoto", message, "photo", photoPathOrFileID, &result)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.MessageID, nil
+}
 
-    client := &http.Client{}
-    resp, err := client.Do(req)
-    if err != nil {
-        log.Println("Error sending request:", err)
-        return 0
-    }
-    defer resp.Body.Close()
+func isFileID(pathOrID string) bool {
+    return len(pathOrID) > 0 && pathOrID[0] == 'A'
+}
 
-    body, err := io.ReadAll(resp.Body)
-    if err != nil {
-        log.Fatal(err)
-        return 0
+func serializeField(value interface{}) string {
+    if text, ok := value.(string); ok {
+        return text
     }
-
-    var response ResponsePostMessage
-    err = json.Unmarshal(body, &response)
+    valueJSON, err := json.Marshal(value)
     if err != nil {
-        log.Fatal(err)
-        return 0
-    }
-    if !response.Ok {
-        return 0
+        log.Println("Error marshalling field:", err)
+        return ""
     }
-
-    return response.Result.MessageID
-}
-
-func isFileID(pathOrID string) bool {
-    return len(pathOrID) > 0 && pathOrID[0] == 'A'
+    return string(valueJSON)
 }
 
 func serializeKeyboard(keyboard interface{}) string {
@@ -462,91 +708,51 @@ func serializeKeyboard(keyboard interface{}) string {
     return string(keyboardJSON)
 }
 
-func (b *Bot) DeleteMessage(chatID int64, messageID int64) {
+func (b *Bot) DeleteMessage(chatID int64, messageID int64) error {
 	message := map[string]interface{}{
 		"chat_id": chatID,
 		"message_id": messageID,
 	}
 
-	messageJSON, err := json.Marshal(message)
-	if err != nil {
-		log.Println("Error marshalling message:", err)
-		return
-	}
-
-	url := "https://api.telegram.org/bot" + b.Token + "/deleteMessage"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(messageJSON))
-	if err != nil {
-		log.Println("Error creating request:", err)
-		return
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error sending request:", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Error response from Telegram: (Status Code: %d)\n", resp.StatusCode)
-		return
-	}
+	return b.callMethod("deleteMessage", message, nil)
 }
  
-func (b *Bot) SendDice(chatID int64, emoji string) int {
-	message := map[string]interface{}{
-		"chat_id": chatID,
-		"emoji":   emoji,
-	}
-
-	messageJSON, err := json.Marshal(message)
+func (b *Bot) SendDice(chatID int64, emoji string, opts ...SendOptions) int {
+	messageID, err := b.TrySendDice(chatID, emoji, opts...)
 	if err != nil {
-		log.Println("Error marshalling message:", err)
+		log.Println("Error sending dice:", err)
 		return 0
 	}
+	return messageID
+}
 
-	url := "https://api.telegram.org/bot" + b.Token + "/sendDice"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(messageJSON))
-	if err != nil {
-		log.Println("Error creating request:", err)
-		return 0
+func (b *Bot) TrySendDice(chatID int64, emoji string, opts ...SendOptions) (int, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"emoji":   emoji,
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error sending request:", err)
-		return 0
-	}
-	defer resp.Body.Close()
+	sendOptions(opts).apply(message, sendDiceOptions)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-		return 0
+	var result MessageID
+	if err := b.callMethod("sendDice", message, &result); err != nil {
+		return 0, err
 	}
+	return result.MessageID, nil
+}
 
-	var response ResponsePostMessage
-	err = json.Unmarshal(body, &response)
+func (b *Bot) EditMessage(chatID int64, messageID int64, text string, parseMode string, keyboards *Keyboards, opts ...SendOptions) int {
+	editedID, err := b.TryEditMessage(chatID, messageID, text, parseMode, keyboards, opts...)
 	if err != nil {
-		log.Fatal(err)
-		return 0
-	}
-	if !response.Ok {
+		log.Println("Error editing message:", err)
 		return 0
 	}
-
-	return response.Result.MessageID
+	return editedID
 }
 
-func (b *Bot) EditMessage(chatID int64, messageID int64, text string, parseMode string, keyboards *Keyboards) int {
-	if len(text) > 1000 {
+func (b *Bot) TryEditMessage(chatID int64, messageID int64, text string, parseMode string, keyboards *Keyboards, opts ...SendOptions) (int, error) {
+	opt := sendOptions(opts)
+	if len(text) > 1000 && len(opt.Entities) == 0 {
 		text = text[:1000] + "..."
 	}
 
@@ -560,57 +766,35 @@ func (b *Bot) EditMessage(chatID int64, messageID int64, text string, parseMode
 		message["parse_mode"] = parseMode
 	}
 
-	if keyboards.Reply != nil {
-		message["reply_markup"] = keyboards.Reply
-	}
-	if keyboards.Inline != nil {
-		message["reply_markup"] = keyboards.Inline
-	}
+	opt.apply(message, editMessageTextOptions)
 
-	messageJSON, err := json.Marshal(message)
+	markup, err := keyboards.InlineMarkup()
 	if err != nil {
-		log.Println("Error marshalling message:", err)
-		return 0
+		return 0, err
 	}
-
-	url := "https://api.telegram.org/bot" + b.Token + "/editMessageText"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(messageJSON))
-	if err != nil {
-		log.Println("Error creating request:", err)
-		return 0
+	if markup != nil {
+		message["reply_markup"] = markup
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error sending request:", err)
-		return 0
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-		return 0
+	var result MessageID
+	if err := b.callMethod("editMessageText", message, &result); err != nil {
+		return 0, err
 	}
+	return result.MessageID, nil
+}
 
-	var response ResponsePostMessage
-	err = json.Unmarshal(body, &response)
+func (b *Bot) SendMessage(chatID int64, text string, parseMode string, keyboards *Keyboards, opts ...SendOptions) int {
+	messageID, err := b.TrySendMessage(chatID, text, parseMode, keyboards, opts...)
 	if err != nil {
-		log.Fatal(err)
-		return 0
-	}
-	if !response.Ok {
+		log.Println("Error sending message:", err)
 		return 0
 	}
-
-	return response.Result.MessageID
+	return messageID
 }
-	
-func (b *Bot) SendMessage(chatID int64, text string, parseMode string, keyboards *Keyboards) int {
-	if len(text) > 10000 {
+
+func (b *Bot) TrySendMessage(chatID int64, text string, parseMode string, keyboards *Keyboards, opts ...SendOptions) (int, error) {
+	opt := sendOptions(opts)
+	if len(text) > 10000 && len(opt.Entities) == 0 {
 		text = text[:10000] + "..."
 	}
 
@@ -623,84 +807,54 @@ func (b *Bot) SendMessage(chatID int64, text string, parseMode string, keyboards
 		message["parse_mode"] = parseMode
 	}
 
-	if keyboards.Reply != nil {
-		message["reply_markup"] = keyboards.Reply
-	}
-	if keyboards.Inline != nil {
-		message["reply_markup"] = keyboards.Inline
-	}
-	
-	messageJSON, err := json.Marshal(message)
-	if err != nil {
-		log.Println("Error marshalling message:", err)
-		return 0
-	}
+	opt.apply(message, sendMessageOptions)
 
-	url := "https://api.telegram.org/bot" + b.Token + "/sendMessage"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(messageJSON))
+	markup, err := keyboards.ReplyMarkup()
 	if err != nil {
-		log.Println("Error creating request:", err)
-		return 0
+		return 0, err
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error sending request:", err)
-		return 0
+	if markup != nil {
+		message["reply_markup"] = markup
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-		return 0
-	}
-
-	var response ResponsePostMessage
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		log.Fatal(err)
-		return 0
+	var result MessageID
+	if err := b.callMethod("sendMessage", message, &result); err != nil {
+		return 0, err
 	}
-	if !response.Ok {
-		return 0
-	}
-
-	return response.Result.MessageID
+	return result.MessageID, nil
 }
 
 func (b *Bot) getUpdates(offset int64) ([]Update, error) {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?offset=%d", b.Token, offset)
-	resp, err := http.Get(url)
+	requestURL := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?offset=%d&timeout=%d", b.Token, offset, pollTimeout)
+	if len(b.AllowedUpdates) > 0 {
+		allowed, err := json.Marshal(b.AllowedUpdates)
+		if err != nil {
+			return nil, err
+		}
+		requestURL += "&allowed_updates=" + url.QueryEscape(string(allowed))
+	}
+	resp, err := b.httpClient().Get(requestURL)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
-	var formattedJSON bytes.Buffer
-	if err := json.Indent(&formattedJSON, body, "", "  "); err != nil {
-		log.Fatalf("Ошибка форматирования JSON: %v", err)
+	if b.recorder != nil {
+		b.recorder.recordUpdates(body)
 	}
-	fmt.Println(formattedJSON.String())
 
 	var updates TelegramResponse
 	err = json.Unmarshal(body, &updates)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 	if !updates.Ok {
-		return nil, fmt.Errorf("Telegram API returned an error:", updates.Result)
+		return nil, fmt.Errorf("Telegram API returned an error: %v", updates.Result)
 	}
 
 	return updates.Result, nil
@@ -709,7 +863,7 @@ func (b *Bot) getUpdates(offset int64) ([]Update, error) {
 func (b *Bot) DownloadFile(fileName, fileID string) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/getFile?file_id=%s", b.Token, fileID)
 
-	resp, err := http.Get(url)
+	resp, err := b.httpClient().Get(url)
 	if err != nil {
 		return err
 	}
@@ -739,7 +893,7 @@ func (b *Bot) DownloadFile(fileName, fileID string) error {
 	}
 	defer out.Close()
 
-	resp2, err := http.Get(fileURL)
+	resp2, err := b.httpClient().Get(fileURL)
 	if err != nil {
 		return err
 	}
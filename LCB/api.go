@@ -0,0 +1,139 @@
+package LCB
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+type APIResponse struct {
+	Ok          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+}
+
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id"`
+	RetryAfter      int   `json:"retry_after"`
+}
+
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	RetryAfter  int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+var defaultHTTPClient = &http.Client{}
+
+func (b *Bot) httpClient() *http.Client {
+	if b.HTTPClient != nil {
+		return b.HTTPClient
+	}
+	return defaultHTTPClient
+}
+
+func (b *Bot) callMethod(method string, message map[string]interface{}, result interface{}) error {
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	url := "https://api.telegram.org/bot" + b.Token + "/" + method
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(messageJSON))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(method, body, result)
+}
+
+func (b *Bot) callMultipart(method string, fields map[string]interface{}, fileField string, filePath string, result interface{}) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	part, err := writer.CreateFormFile(fileField, filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		if err := writer.WriteField(key, serializeField(value)); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	url := "https://api.telegram.org/bot" + b.Token + "/" + method
+	req, err := http.NewRequest("POST", url, &buffer)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(method, body, result)
+}
+
+func decodeResponse(method string, body []byte, result interface{}) error {
+	var response APIResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	if !response.Ok {
+		apiErr := &APIError{Method: method, Code: response.ErrorCode, Description: response.Description}
+		if response.Parameters != nil {
+			apiErr.RetryAfter = response.Parameters.RetryAfter
+		}
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,409 @@
+package LCB
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RecipientPending     = "pending"
+	RecipientSent        = "sent"
+	RecipientBlocked     = "blocked"
+	RecipientDeactivated = "deactivated"
+	RecipientFailed      = "failed"
+)
+
+var ErrBroadcastPaused = errors.New("broadcast paused")
+var ErrBroadcastRunning = errors.New("broadcast already running")
+
+type RecipientSource func() ([]int64, error)
+
+func StaticRecipients(chatIDs ...int64) RecipientSource {
+	return func() ([]int64, error) {
+		return chatIDs, nil
+	}
+}
+
+type RecipientStatus struct {
+	ChatID    int64     `json:"chat_id"`
+	Status    string    `json:"status"`
+	MessageID int       `json:"message_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+type BroadcastStore interface {
+	LoadBroadcast(jobID string) (map[int64]RecipientStatus, error)
+	SaveRecipient(jobID string, status RecipientStatus) error
+}
+
+type BroadcastProgress struct {
+	JobID       string
+	Total       int
+	Sent        int
+	Blocked     int
+	Deactivated int
+	Failed      int
+	Pending     int
+	Done        bool
+}
+
+type BroadcastConfig struct {
+	ID         string
+	Recipients RecipientSource
+	Text       string
+	ParseMode  string
+	Keyboards  *Keyboards
+	Options    SendOptions
+	Send       func(chatID int64) (int, error)
+	Rate       float64
+	MaxRetries int
+	Store      BroadcastStore
+	OnProgress func(progress BroadcastProgress)
+}
+
+type Broadcast struct {
+	config   BroadcastConfig
+	bot      *Bot
+	mu       sync.Mutex
+	running  bool
+	paused   bool
+	progress BroadcastProgress
+	statuses map[int64]RecipientStatus
+	done     chan struct{}
+	err      error
+}
+
+func (b *Bot) NewBroadcast(config BroadcastConfig) *Broadcast {
+	if config.Rate <= 0 {
+		config.Rate = 25
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryBroadcastStore()
+	}
+	bc := &Broadcast{
+		config:   config,
+		bot:      b,
+		progress: BroadcastProgress{JobID: config.ID},
+		statuses: make(map[int64]RecipientStatus),
+	}
+	if bc.config.Send == nil {
+		bc.config.Send = func(chatID int64) (int, error) {
+			return b.TrySendMessage(chatID, config.Text, config.ParseMode, config.Keyboards, config.Options)
+		}
+	}
+	return bc
+}
+
+// Start runs the broadcast in the background. Recipients already marked as
+// sent, blocked or deactivated in the store are skipped, so calling Start
+// after Pause or a restart continues where the job stopped.
+func (bc *Broadcast) Start() error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.running {
+		return ErrBroadcastRunning
+	}
+	bc.running = true
+	bc.paused = false
+	bc.err = nil
+	bc.done = make(chan struct{})
+	go func() {
+		err := bc.run()
+		bc.mu.Lock()
+		bc.running = false
+		bc.err = err
+		close(bc.done)
+		bc.mu.Unlock()
+	}()
+	return nil
+}
+
+func (bc *Broadcast) Run() error {
+	if err := bc.Start(); err != nil {
+		return err
+	}
+	return bc.Wait()
+}
+
+func (bc *Broadcast) Pause() {
+	bc.mu.Lock()
+	bc.paused = true
+	bc.mu.Unlock()
+}
+
+func (bc *Broadcast) Resume() error {
+	return bc.Start()
+}
+
+func (bc *Broadcast) Wait() error {
+	bc.mu.Lock()
+	done := bc.done
+	bc.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	<-done
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	return bc.err
+}
+
+func (bc *Broadcast) Progress() BroadcastProgress {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	return bc.progress
+}
+
+func (bc *Broadcast) Status(chatID int64) RecipientStatus {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if status, ok := bc.statuses[chatID]; ok {
+		return status
+	}
+	return RecipientStatus{ChatID: chatID, Status: RecipientPending}
+}
+
+func (bc *Broadcast) isPaused() bool {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	return bc.paused
+}
+
+func (bc *Broadcast) run() error {
+	recipients, err := bc.config.Recipients()
+	if err != nil {
+		return err
+	}
+	stored, err := bc.config.Store.LoadBroadcast(bc.config.ID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int64]bool, len(recipients))
+	queue := make([]int64, 0, len(recipients))
+	bc.mu.Lock()
+	bc.progress = BroadcastProgress{JobID: bc.config.ID}
+	for _, chatID := range recipients {
+		if seen[chatID] {
+			continue
+		}
+		seen[chatID] = true
+		bc.progress.Total++
+		status, ok := stored[chatID]
+		if ok && status.Status != RecipientFailed {
+			bc.statuses[chatID] = status
+			bc.countStatus(status.Status)
+			continue
+		}
+		queue = append(queue, chatID)
+	}
+	bc.progress.Pending = len(queue)
+	bc.mu.Unlock()
+	bc.reportProgress()
+
+	interval := time.Duration(float64(time.Second) / bc.config.Rate)
+	next := time.Now()
+	for _, chatID := range queue {
+		if bc.isPaused() {
+			return ErrBroadcastPaused
+		}
+		if wait := time.Until(next); wait > 0 {
+			time.Sleep(wait)
+		}
+		next = time.Now().Add(interval)
+
+		status := bc.deliver(chatID)
+		if err := bc.config.Store.SaveRecipient(bc.config.ID, status); err != nil {
+			return err
+		}
+
+		bc.mu.Lock()
+		bc.statuses[chatID] = status
+		bc.progress.Pending--
+		bc.countStatus(status.Status)
+		bc.mu.Unlock()
+		bc.reportProgress()
+	}
+
+	bc.mu.Lock()
+	bc.progress.Done = true
+	bc.mu.Unlock()
+	bc.reportProgress()
+	return nil
+}
+
+func (bc *Broadcast) deliver(chatID int64) RecipientStatus {
+	status := RecipientStatus{ChatID: chatID}
+	for attempt := 0; attempt < bc.config.MaxRetries; {
+		messageID, err := bc.config.Send(chatID)
+		if err == nil {
+			status.Status = RecipientSent
+			status.MessageID = messageID
+			status.Error = ""
+			break
+		}
+		status.Error = err.Error()
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if apiErr.RetryAfter > 0 {
+				attempt++
+				status.Status = RecipientFailed
+				if attempt < bc.config.MaxRetries {
+					time.Sleep(time.Duration(apiErr.RetryAfter) * time.Second)
+				}
+				continue
+			}
+			if apiErr.Code == 403 {
+				if strings.Contains(apiErr.Description, "deactivated") {
+					status.Status = RecipientDeactivated
+				} else {
+					status.Status = RecipientBlocked
+				}
+				break
+			}
+			if apiErr.Code == 400 {
+				status.Status = RecipientFailed
+				break
+			}
+		}
+		attempt++
+		status.Status = RecipientFailed
+		if attempt < bc.config.MaxRetries {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}
+	status.At = time.Now()
+	return status
+}
+
+func (bc *Broadcast) countStatus(status string) {
+	switch status {
+	case RecipientSent:
+		bc.progress.Sent++
+	case RecipientBlocked:
+		bc.progress.Blocked++
+	case RecipientDeactivated:
+		bc.progress.Deactivated++
+	case RecipientFailed:
+		bc.progress.Failed++
+	}
+}
+
+func (bc *Broadcast) reportProgress() {
+	if bc.config.OnProgress != nil {
+		bc.config.OnProgress(bc.Progress())
+	}
+}
+
+type MemoryBroadcastStore struct {
+	mu   sync.Mutex
+	jobs map[string]map[int64]RecipientStatus
+}
+
+func NewMemoryBroadcastStore() *MemoryBroadcastStore {
+	return &MemoryBroadcastStore{jobs: make(map[string]map[int64]RecipientStatus)}
+}
+
+func (s *MemoryBroadcastStore) LoadBroadcast(jobID string) (map[int64]RecipientStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[int64]RecipientStatus, len(s.jobs[jobID]))
+	for chatID, status := range s.jobs[jobID] {
+		result[chatID] = status
+	}
+	return result, nil
+}
+
+func (s *MemoryBroadcastStore) SaveRecipient(jobID string, status RecipientStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs[jobID] == nil {
+		s.jobs[jobID] = make(map[int64]RecipientStatus)
+	}
+	s.jobs[jobID][status.ChatID] = status
+	return nil
+}
+
+// FileBroadcastStore appends one JSON line per recipient to <dir>/<jobID>.jsonl.
+type FileBroadcastStore struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+func NewFileBroadcastStore(dir string) *FileBroadcastStore {
+	return &FileBroadcastStore{Dir: dir}
+}
+
+// path rejects IDs that could name a file outside Dir.
+func (s *FileBroadcastStore) path(jobID string) (string, error) {
+	if jobID == "" || jobID == "." || jobID == ".." || strings.ContainsAny(jobID, `/\`) {
+		return "", fmt.Errorf("invalid broadcast ID %q", jobID)
+	}
+	return filepath.Join(s.Dir, jobID+".jsonl"), nil
+}
+
+func (s *FileBroadcastStore) LoadBroadcast(jobID string) (map[int64]RecipientStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(jobID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]RecipientStatus)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var status RecipientStatus
+		if err := json.Unmarshal(scanner.Bytes(), &status); err != nil {
+			// a crash can leave the last line half written
+			continue
+		}
+		result[status.ChatID] = status
+	}
+	return result, scanner.Err()
+}
+
+func (s *FileBroadcastStore) SaveRecipient(jobID string, status RecipientStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(jobID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	line, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,325 @@
+package LCB
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxCallbackDataLen   = 64
+	callbackTokenPrefix  = "~t"
+	callbackSignedPrefix = "~s"
+	callbackSignatureLen = 11
+)
+
+type CallbackCodec struct {
+	TTL         time.Duration
+	Strict      bool
+	ExpiredText string
+	Store       CallbackStore
+	secret      []byte
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+type StoredCallback struct {
+	Token   string    `json:"token"`
+	Payload string    `json:"payload"`
+	Created time.Time `json:"created"`
+}
+
+// CallbackStore keeps payloads that do not fit into callback_data. By default
+// they are kept in the bot's state storage next to SetState data; use a
+// persistent store so buttons in already sent messages keep working after a
+// restart.
+type CallbackStore interface {
+	SaveCallback(callback StoredCallback) error
+	LoadCallback(token string) (StoredCallback, bool, error)
+	DeleteCallbacksBefore(created time.Time) error
+}
+
+// UseCallbackCodec enables the codec. With a secret, callback data that is
+// neither signed nor a stored token is always rejected, whatever Strict says.
+func (b *Bot) UseCallbackCodec(secret string) *CallbackCodec {
+	codec := &CallbackCodec{
+		TTL:         24 * time.Hour,
+		ExpiredText: "This button has expired.",
+		Store:       stateCallbackStore{bot: b},
+	}
+	if secret != "" {
+		codec.secret = []byte(secret)
+		codec.Strict = true
+	}
+	b.callbackCodec = codec
+	return codec
+}
+
+func (b *Bot) CallbackData(payload string) string {
+	data, err := b.TryCallbackData(payload)
+	if err != nil {
+		log.Println("Error encoding callback data:", err)
+		return ""
+	}
+	return data
+}
+
+func (b *Bot) TryCallbackData(payload string) (string, error) {
+	if b.callbackCodec == nil {
+		return payload, nil
+	}
+	return b.callbackCodec.Encode(payload)
+}
+
+func (b *Bot) CallbackBtn(text string, payload string) InlineKeyboardButton {
+	return Btn(text, b.CallbackData(payload))
+}
+
+func (c *CallbackCodec) Encode(payload string) (string, error) {
+	if c.secret != nil {
+		signed := callbackSignedPrefix + c.sign(payload) + payload
+		if len(signed) <= maxCallbackDataLen {
+			return signed, nil
+		}
+	} else if len(payload) <= maxCallbackDataLen && !strings.HasPrefix(payload, "~") {
+		return payload, nil
+	}
+	token, err := c.store(payload)
+	if err != nil {
+		return "", err
+	}
+	return callbackTokenPrefix + token, nil
+}
+
+func (c *CallbackCodec) Decode(data string) (string, bool) {
+	switch {
+	case strings.HasPrefix(data, callbackTokenPrefix):
+		stored, ok, err := c.Store.LoadCallback(strings.TrimPrefix(data, callbackTokenPrefix))
+		if err != nil {
+			log.Println("Error loading callback data:", err)
+			return "", false
+		}
+		if !ok || c.expired(stored) {
+			return "", false
+		}
+		return stored.Payload, true
+	case strings.HasPrefix(data, callbackSignedPrefix) && c.secret != nil:
+		data = strings.TrimPrefix(data, callbackSignedPrefix)
+		if len(data) < callbackSignatureLen {
+			return "", false
+		}
+		signature, payload := data[:callbackSignatureLen], data[callbackSignatureLen:]
+		if !hmac.Equal([]byte(signature), []byte(c.sign(payload))) {
+			return "", false
+		}
+		return payload, true
+	}
+	if c.Strict || c.secret != nil {
+		return "", false
+	}
+	return data, true
+}
+
+func (c *CallbackCodec) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func (c *CallbackCodec) store(payload string) (string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("callback token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	c.cleanup()
+	if err := c.Store.SaveCallback(StoredCallback{Token: token, Payload: payload, Created: time.Now()}); err != nil {
+		return "", fmt.Errorf("saving callback data: %w", err)
+	}
+	return token, nil
+}
+
+func (c *CallbackCodec) expired(stored StoredCallback) bool {
+	return c.TTL > 0 && time.Since(stored.Created) > c.TTL
+}
+
+func (c *CallbackCodec) cleanup() {
+	c.mu.Lock()
+	if c.TTL <= 0 || time.Since(c.lastCleanup) < time.Minute {
+		c.mu.Unlock()
+		return
+	}
+	c.lastCleanup = time.Now()
+	c.mu.Unlock()
+
+	if err := c.Store.DeleteCallbacksBefore(time.Now().Add(-c.TTL)); err != nil {
+		log.Println("Error cleaning up callback data:", err)
+	}
+}
+
+func (b *Bot) dropInvalidCallback(query *CallbackQuery) {
+	log.Println("Dropping callback with invalid data:", query.Data)
+	text := b.callbackCodec.ExpiredText
+	go func() {
+		if err := b.AnswerCallbackQuery(query.ID, text, false, "", 0); err != nil {
+			log.Println("Error answering dropped callback:", err)
+		}
+	}()
+}
+
+// callbackStateUserID is the state slot for stored payloads; no Telegram user
+// has ID 0.
+const (
+	callbackStateUserID    int64 = 0
+	callbackStateKeyPrefix       = "callback:"
+)
+
+// stateCallbackStore keeps payloads in the bot's state storage.
+type stateCallbackStore struct {
+	bot *Bot
+}
+
+func (s stateCallbackStore) SaveCallback(callback StoredCallback) error {
+	s.bot.SetState(callbackStateUserID, callbackStateKeyPrefix+callback.Token, callback)
+	return nil
+}
+
+func (s stateCallbackStore) LoadCallback(token string) (StoredCallback, bool, error) {
+	callback, ok := s.bot.GetState(callbackStateUserID, callbackStateKeyPrefix+token).(StoredCallback)
+	return callback, ok, nil
+}
+
+func (s stateCallbackStore) DeleteCallbacksBefore(created time.Time) error {
+	s.bot.stateMu.Lock()
+	defer s.bot.stateMu.Unlock()
+	for key, value := range s.bot.state[callbackStateUserID] {
+		callback, ok := value.(StoredCallback)
+		if ok && strings.HasPrefix(key, callbackStateKeyPrefix) && callback.Created.Before(created) {
+			delete(s.bot.state[callbackStateUserID], key)
+		}
+	}
+	return nil
+}
+
+type MemoryCallbackStore struct {
+	mu        sync.Mutex
+	callbacks map[string]StoredCallback
+}
+
+func NewMemoryCallbackStore() *MemoryCallbackStore {
+	return &MemoryCallbackStore{callbacks: make(map[string]StoredCallback)}
+}
+
+func (s *MemoryCallbackStore) SaveCallback(callback StoredCallback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks[callback.Token] = callback
+	return nil
+}
+
+func (s *MemoryCallbackStore) LoadCallback(token string) (StoredCallback, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	callback, ok := s.callbacks[token]
+	return callback, ok, nil
+}
+
+func (s *MemoryCallbackStore) DeleteCallbacksBefore(created time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token, callback := range s.callbacks {
+		if callback.Created.Before(created) {
+			delete(s.callbacks, token)
+		}
+	}
+	return nil
+}
+
+// FileCallbackStore keeps payloads in memory and appends every new one to a
+// JSONL file, which is read back on start and compacted on cleanup.
+type FileCallbackStore struct {
+	Path   string
+	memory *MemoryCallbackStore
+	mu     sync.Mutex
+}
+
+func NewFileCallbackStore(path string) (*FileCallbackStore, error) {
+	store := &FileCallbackStore{Path: path, memory: NewMemoryCallbackStore()}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var callback StoredCallback
+		if err := json.Unmarshal(scanner.Bytes(), &callback); err != nil {
+			continue
+		}
+		store.memory.SaveCallback(callback)
+	}
+	return store, scanner.Err()
+}
+
+func (s *FileCallbackStore) SaveCallback(callback StoredCallback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	line, err := json.Marshal(callback)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.memory.SaveCallback(callback)
+}
+
+func (s *FileCallbackStore) LoadCallback(token string) (StoredCallback, bool, error) {
+	return s.memory.LoadCallback(token)
+}
+
+func (s *FileCallbackStore) DeleteCallbacksBefore(created time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.memory.DeleteCallbacksBefore(created); err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	s.memory.mu.Lock()
+	for _, callback := range s.memory.callbacks {
+		line, err := json.Marshal(callback)
+		if err != nil {
+			s.memory.mu.Unlock()
+			return err
+		}
+		buffer.Write(append(line, '\n'))
+	}
+	s.memory.mu.Unlock()
+
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, buffer.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
@@ -0,0 +1,69 @@
+package LCB
+
+import (
+	"log"
+	"time"
+)
+
+func (b *Bot) AnswerCallbackQuery(callbackQueryID string, text string, showAlert bool, url string, cacheTime int) error {
+	b.takePendingCallback(callbackQueryID)
+
+	message := map[string]interface{}{
+		"callback_query_id": callbackQueryID,
+	}
+	if text != "" {
+		message["text"] = text
+	}
+	if showAlert {
+		message["show_alert"] = true
+	}
+	if url != "" {
+		message["url"] = url
+	}
+	if cacheTime > 0 {
+		message["cache_time"] = cacheTime
+	}
+
+	return b.callMethod("answerCallbackQuery", message, nil)
+}
+
+func (b *Bot) AutoAnswerCallbacks(deadline time.Duration) {
+	b.callbackMu.Lock()
+	defer b.callbackMu.Unlock()
+
+	b.autoAnswer = deadline
+	if b.pendingCallbacks == nil {
+		b.pendingCallbacks = make(map[string]bool)
+	}
+}
+
+func (b *Bot) trackCallback(query *CallbackQuery) {
+	b.callbackMu.Lock()
+	deadline := b.autoAnswer
+	if deadline <= 0 {
+		b.callbackMu.Unlock()
+		return
+	}
+	b.pendingCallbacks[query.ID] = true
+	b.callbackMu.Unlock()
+
+	time.AfterFunc(deadline, func() {
+		if !b.takePendingCallback(query.ID) {
+			return
+		}
+		if err := b.AnswerCallbackQuery(query.ID, "", false, "", 0); err != nil {
+			log.Println("Error auto-answering callback:", err)
+		}
+	})
+}
+
+func (b *Bot) takePendingCallback(callbackQueryID string) bool {
+	b.callbackMu.Lock()
+	defer b.callbackMu.Unlock()
+
+	if !b.pendingCallbacks[callbackQueryID] {
+		return false
+	}
+	delete(b.pendingCallbacks, callbackQueryID)
+	return true
+}
@@ -0,0 +1,441 @@
+package LCB
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	CaptchaButton     = "button"
+	CaptchaArithmetic = "arithmetic"
+)
+
+type CaptchaConfig struct {
+	Mode       string
+	Timeout    time.Duration
+	Text       string
+	ButtonText string
+	Disabled   bool
+}
+
+type Captcha struct {
+	Default    CaptchaConfig
+	OnVerified func(chatID int64, userID int64)
+	OnFailed   func(chatID int64, userID int64)
+	bot        *Bot
+	mu         sync.Mutex
+	chats      map[int64]CaptchaConfig
+	pending    map[string]*captchaChallenge
+	verified   map[string]time.Time
+	store      CaptchaStore
+}
+
+// StoredChallenge records a restricted member so that a restart can lift the
+// restriction instead of leaving the user muted forever.
+type StoredChallenge struct {
+	ChatID    int64 `json:"chat_id"`
+	UserID    int64 `json:"user_id"`
+	MessageID int64 `json:"message_id,omitempty"`
+}
+
+type CaptchaStore interface {
+	LoadChallenges() ([]StoredChallenge, error)
+	SaveChallenge(challenge StoredChallenge) error
+	DeleteChallenge(chatID int64, userID int64) error
+}
+
+type captchaChallenge struct {
+	chatID    int64
+	userID    int64
+	messageID int64
+	answer    string
+	timer     *time.Timer
+}
+
+func (b *Bot) NewCaptcha(config CaptchaConfig) *Captcha {
+	c := &Captcha{
+		Default:  config,
+		bot:      b,
+		chats:    make(map[int64]CaptchaConfig),
+		pending:  make(map[string]*captchaChallenge),
+		verified: make(map[string]time.Time),
+	}
+	b.AllowUpdate("chat_member")
+	b.AddHandler(FilterNewChatMembers{}, c.handleJoin)
+	b.AddHandler(FilterCallbackPrefix{Prefix: "cap:"}, c.handleCallback)
+	return c
+}
+
+func (c *Captcha) SetChatConfig(chatID int64, config CaptchaConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chats[chatID] = config
+}
+
+func (c *Captcha) DisableChat(chatID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	config, ok := c.chats[chatID]
+	if !ok {
+		config = c.Default
+	}
+	config.Disabled = true
+	c.chats[chatID] = config
+}
+
+func (c *Captcha) config(chatID int64) CaptchaConfig {
+	c.mu.Lock()
+	config, ok := c.chats[chatID]
+	c.mu.Unlock()
+	if !ok {
+		config = c.Default
+	}
+
+	if config.Mode == "" {
+		config.Mode = CaptchaButton
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Minute
+	}
+	if config.Text == "" {
+		config.Text = "please confirm you are human to start chatting."
+	}
+	if config.ButtonText == "" {
+		config.ButtonText = "I'm not a robot"
+	}
+	return config
+}
+
+func captchaKey(chatID int64, userID int64) string {
+	return strconv.FormatInt(chatID, 10) + ":" + strconv.FormatInt(userID, 10)
+}
+
+func (c *Captcha) handleJoin(update Update) {
+	chatID, users := newChatMembers(update)
+	config := c.config(chatID)
+	if config.Disabled {
+		return
+	}
+	for _, user := range users {
+		if !user.IsBot {
+			c.challenge(chatID, user, config)
+		}
+	}
+}
+
+func (c *Captcha) challenge(chatID int64, user User, config CaptchaConfig) {
+	key := captchaKey(chatID, user.ID)
+
+	c.mu.Lock()
+	for verifiedKey, verifiedAt := range c.verified {
+		if time.Since(verifiedAt) >= time.Minute {
+			delete(c.verified, verifiedKey)
+		}
+	}
+	if _, ok := c.pending[key]; ok || time.Since(c.verified[key]) < time.Minute {
+		c.mu.Unlock()
+		return
+	}
+	challenge := &captchaChallenge{chatID: chatID, userID: user.ID}
+	c.pending[key] = challenge
+	c.mu.Unlock()
+
+	if err := c.bot.RestrictChatMember(chatID, user.ID, ChatPermissions{}, 0); err != nil {
+		log.Println("Error restricting new member:", err)
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+		return
+	}
+	c.save(challenge)
+
+	name := user.FirstName
+	if name == "" {
+		name = user.Username
+	}
+	text := NewText().Mention(name, user.ID).Plain(", " + config.Text)
+
+	var buttons []InlineKeyboardButton
+	prefix := "cap:" + key + ":"
+	if config.Mode == CaptchaArithmetic {
+		x, y := rand.Intn(10)+1, rand.Intn(10)+1
+		challenge.answer = strconv.Itoa(x + y)
+		text.Plain(fmt.Sprintf("\n%d + %d = ?", x, y))
+
+		options := []int{x + y}
+		for len(options) < 4 {
+			option := x + y + rand.Intn(9) - 4
+			if option > 0 && !containsInt(options, option) {
+				options = append(options, option)
+			}
+		}
+		rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+		for _, option := range options {
+			buttons = append(buttons, c.bot.CallbackBtn(strconv.Itoa(option), prefix+strconv.Itoa(option)))
+		}
+	} else {
+		challenge.answer = "ok"
+		buttons = append(buttons, c.bot.CallbackBtn(config.ButtonText, prefix+"ok"))
+	}
+
+	plain, entities := text.Entities()
+	messageID, err := c.bot.TrySendMessage(chatID, plain, "", NewInline().Row(buttons...).Keyboards(), SendOptions{Entities: entities})
+	if err != nil {
+		// The user never saw a challenge, so they must not be kicked for ignoring it.
+		log.Println("Error sending captcha:", err)
+		if c.take(key) == challenge {
+			c.lift(chatID, user.ID)
+			c.forget(challenge)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	challenge.messageID = int64(messageID)
+	if c.pending[key] != challenge {
+		c.mu.Unlock()
+		c.cleanup(challenge)
+		return
+	}
+	challenge.timer = time.AfterFunc(config.Timeout, func() {
+		c.fail(key)
+	})
+	c.mu.Unlock()
+	c.save(challenge)
+}
+
+func (c *Captcha) take(key string) *captchaChallenge {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	challenge, ok := c.pending[key]
+	if !ok {
+		return nil
+	}
+	delete(c.pending, key)
+	if challenge.timer != nil {
+		challenge.timer.Stop()
+	}
+	return challenge
+}
+
+func (c *Captcha) fail(key string) {
+	challenge := c.take(key)
+	if challenge == nil {
+		return
+	}
+	c.cleanup(challenge)
+
+	// Banning and unbanning right away removes the user without blocking a later rejoin.
+	if err := c.bot.BanChatMember(challenge.chatID, challenge.userID, 0, false); err != nil {
+		log.Println("Error removing unverified member:", err)
+	} else if err := c.bot.UnbanChatMember(challenge.chatID, challenge.userID, true); err != nil {
+		log.Println("Error unbanning unverified member:", err)
+	}
+
+	if c.OnFailed != nil {
+		c.OnFailed(challenge.chatID, challenge.userID)
+	}
+}
+
+func (c *Captcha) pass(key string) {
+	challenge := c.take(key)
+	if challenge == nil {
+		return
+	}
+	c.mu.Lock()
+	c.verified[key] = time.Now()
+	c.mu.Unlock()
+	c.cleanup(challenge)
+	c.lift(challenge.chatID, challenge.userID)
+
+	if c.OnVerified != nil {
+		c.OnVerified(challenge.chatID, challenge.userID)
+	}
+}
+
+// lift gives the member the chat's default permissions back.
+func (c *Captcha) lift(chatID int64, userID int64) {
+	permissions := AllChatPermissions()
+	if chat, err := c.bot.GetChat(chatID); err == nil && chat.Permissions != nil {
+		permissions = *chat.Permissions
+	}
+	if err := c.bot.RestrictChatMember(chatID, userID, permissions, 0); err != nil {
+		log.Println("Error lifting restrictions:", err)
+	}
+}
+
+func (c *Captcha) cleanup(challenge *captchaChallenge) {
+	c.forget(challenge)
+	if challenge.messageID == 0 {
+		return
+	}
+	if err := c.bot.DeleteMessage(challenge.chatID, challenge.messageID); err != nil {
+		log.Println("Error deleting captcha message:", err)
+	}
+}
+
+func (c *Captcha) save(challenge *captchaChallenge) {
+	if c.store == nil {
+		return
+	}
+	stored := StoredChallenge{ChatID: challenge.chatID, UserID: challenge.userID, MessageID: challenge.messageID}
+	if err := c.store.SaveChallenge(stored); err != nil {
+		log.Println("Error saving captcha challenge:", err)
+	}
+}
+
+func (c *Captcha) forget(challenge *captchaChallenge) {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.DeleteChallenge(challenge.chatID, challenge.userID); err != nil {
+		log.Println("Error deleting captcha challenge:", err)
+	}
+}
+
+// UseStore keeps track of restricted members in store. Challenges left over
+// from a previous run cannot be answered any more, so their restrictions are
+// lifted and their messages deleted.
+func (c *Captcha) UseStore(store CaptchaStore) error {
+	stored, err := store.LoadChallenges()
+	if err != nil {
+		return err
+	}
+	c.store = store
+	for _, s := range stored {
+		challenge := &captchaChallenge{chatID: s.ChatID, userID: s.UserID, messageID: s.MessageID}
+		c.lift(s.ChatID, s.UserID)
+		c.cleanup(challenge)
+	}
+	return nil
+}
+
+func (c *Captcha) handleCallback(update Update) {
+	query := update.CallbackQuery
+	parts := strings.Split(strings.TrimPrefix(query.Data, "cap:"), ":")
+	if len(parts) != 3 || query.From == nil {
+		return
+	}
+	key := parts[0] + ":" + parts[1]
+	if parts[1] != strconv.FormatInt(query.From.ID, 10) {
+		c.bot.AnswerCallbackQuery(query.ID, "This check is for another user.", true, "", 0)
+		return
+	}
+
+	c.mu.Lock()
+	challenge, ok := c.pending[key]
+	c.mu.Unlock()
+	if !ok {
+		c.bot.AnswerCallbackQuery(query.ID, "", false, "", 0)
+		return
+	}
+
+	if parts[2] == challenge.answer {
+		c.bot.AnswerCallbackQuery(query.ID, "Thanks, welcome!", false, "", 0)
+		c.pass(key)
+	} else {
+		c.bot.AnswerCallbackQuery(query.ID, "Wrong answer.", true, "", 0)
+		c.fail(key)
+	}
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// FileCaptchaStore keeps pending challenges in a single JSON file, rewritten on
+// every change.
+type FileCaptchaStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileCaptchaStore(path string) *FileCaptchaStore {
+	return &FileCaptchaStore{Path: path}
+}
+
+func (s *FileCaptchaStore) read() (map[string]StoredChallenge, error) {
+	challenges := make(map[string]StoredChallenge)
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return challenges, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return challenges, nil
+	}
+	if err := json.Unmarshal(data, &challenges); err != nil {
+		return nil, fmt.Errorf("captcha store %s: %w", s.Path, err)
+	}
+	return challenges, nil
+}
+
+func (s *FileCaptchaStore) write(challenges map[string]StoredChallenge) error {
+	data, err := json.MarshalIndent(challenges, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+func (s *FileCaptchaStore) LoadChallenges() ([]StoredChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	challenges := make([]StoredChallenge, 0, len(stored))
+	for _, challenge := range stored {
+		challenges = append(challenges, challenge)
+	}
+	return challenges, nil
+}
+
+func (s *FileCaptchaStore) SaveChallenge(challenge StoredChallenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	challenges, err := s.read()
+	if err != nil {
+		return err
+	}
+	challenges[captchaKey(challenge.ChatID, challenge.UserID)] = challenge
+	return s.write(challenges)
+}
+
+func (s *FileCaptchaStore) DeleteChallenge(chatID int64, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	challenges, err := s.read()
+	if err != nil {
+		return err
+	}
+	key := captchaKey(chatID, userID)
+	if _, ok := challenges[key]; !ok {
+		return nil
+	}
+	delete(challenges, key)
+	return s.write(challenges)
+}
@@ -0,0 +1,88 @@
+package LCB
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	ChatActionTyping          = "typing"
+	ChatActionUploadPhoto     = "upload_photo"
+	ChatActionRecordVideo     = "record_video"
+	ChatActionUploadVideo     = "upload_video"
+	ChatActionRecordVoice     = "record_voice"
+	ChatActionUploadVoice     = "upload_voice"
+	ChatActionUploadDocument  = "upload_document"
+	ChatActionChooseSticker   = "choose_sticker"
+	ChatActionFindLocation    = "find_location"
+	ChatActionRecordVideoNote = "record_video_note"
+	ChatActionUploadVideoNote = "upload_video_note"
+)
+
+// Telegram clears a chat action after 5 seconds, so it is refreshed a bit earlier.
+const chatActionInterval = 4 * time.Second
+
+var sendChatActionOptions = []string{"message_thread_id"}
+
+func (b *Bot) SendChatAction(chatID int64, action string, opts ...SendOptions) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"action":  action,
+	}
+
+	sendOptions(opts).apply(message, sendChatActionOptions)
+
+	return b.callMethod("sendChatAction", message, nil)
+}
+
+func (b *Bot) KeepChatAction(chatID int64, action string, opts ...SendOptions) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(chatActionInterval)
+		defer ticker.Stop()
+		for {
+			if err := b.SendChatAction(chatID, action, opts...); err != nil {
+				log.Println("Error sending chat action:", err)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// WithChatAction shows action while callback runs. The action goes to the
+// forum topic the update came from unless opts name another thread.
+func (b *Bot) WithChatAction(action string, callback func(update Update), opts ...SendOptions) func(update Update) {
+	return func(update Update) {
+		chatID := updateChatID(update)
+		if chatID != 0 {
+			opt := sendOptions(opts)
+			if opt.MessageThreadID == 0 {
+				opt.MessageThreadID = updateThreadID(update)
+			}
+			stop := b.KeepChatAction(chatID, action, opt)
+			defer stop()
+		}
+		callback(update)
+	}
+}
+
+func updateThreadID(update Update) int64 {
+	message := update.Message
+	if message == nil && update.CallbackQuery != nil {
+		message = update.CallbackQuery.Message
+	}
+	if message == nil || !message.IsTopicMessage {
+		return 0
+	}
+	return message.MessageThreadID
+}
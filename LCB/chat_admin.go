@@ -0,0 +1,400 @@
+package LCB
+
+import (
+	"encoding/json"
+	"log"
+)
+
+const (
+	ChatMemberStatusOwner         = "creator"
+	ChatMemberStatusAdministrator = "administrator"
+	ChatMemberStatusMember        = "member"
+	ChatMemberStatusRestricted    = "restricted"
+	ChatMemberStatusLeft          = "left"
+	ChatMemberStatusBanned        = "kicked"
+)
+
+type ChatPermissions struct {
+	CanSendMessages       bool `json:"can_send_messages"`
+	CanSendAudios         bool `json:"can_send_audios"`
+	CanSendDocuments      bool `json:"can_send_documents"`
+	CanSendPhotos         bool `json:"can_send_photos"`
+	CanSendVideos         bool `json:"can_send_videos"`
+	CanSendVideoNotes     bool `json:"can_send_video_notes"`
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes"`
+	CanSendPolls          bool `json:"can_send_polls"`
+	CanSendOtherMessages  bool `json:"can_send_other_messages"`
+	CanAddWebPagePreviews bool `json:"can_add_web_page_previews"`
+	CanChangeInfo         bool `json:"can_change_info"`
+	CanInviteUsers        bool `json:"can_invite_users"`
+	CanPinMessages        bool `json:"can_pin_messages"`
+	CanManageTopics       bool `json:"can_manage_topics"`
+}
+
+func AllChatPermissions() ChatPermissions {
+	return ChatPermissions{
+		CanSendMessages:       true,
+		CanSendAudios:         true,
+		CanSendDocuments:      true,
+		CanSendPhotos:         true,
+		CanSendVideos:         true,
+		CanSendVideoNotes:     true,
+		CanSendVoiceNotes:     true,
+		CanSendPolls:          true,
+		CanSendOtherMessages:  true,
+		CanAddWebPagePreviews: true,
+		CanChangeInfo:         true,
+		CanInviteUsers:        true,
+		CanPinMessages:        true,
+		CanManageTopics:       true,
+	}
+}
+
+type ChatAdministratorRights struct {
+	IsAnonymous         bool `json:"is_anonymous"`
+	CanManageChat       bool `json:"can_manage_chat"`
+	CanDeleteMessages   bool `json:"can_delete_messages"`
+	CanManageVideoChats bool `json:"can_manage_video_chats"`
+	CanRestrictMembers  bool `json:"can_restrict_members"`
+	CanPromoteMembers   bool `json:"can_promote_members"`
+	CanChangeInfo       bool `json:"can_change_info"`
+	CanInviteUsers      bool `json:"can_invite_users"`
+	CanPostStories      bool `json:"can_post_stories"`
+	CanEditStories      bool `json:"can_edit_stories"`
+	CanDeleteStories    bool `json:"can_delete_stories"`
+	CanPostMessages     bool `json:"can_post_messages,omitempty"`
+	CanEditMessages     bool `json:"can_edit_messages,omitempty"`
+	CanPinMessages      bool `json:"can_pin_messages,omitempty"`
+	CanManageTopics     bool `json:"can_manage_topics,omitempty"`
+}
+
+type ChatMember interface {
+	MemberStatus() string
+	MemberUser() *User
+}
+
+type ChatMemberOwner struct {
+	Status      string `json:"status"`
+	User        *User  `json:"user"`
+	IsAnonymous bool   `json:"is_anonymous"`
+	CustomTitle string `json:"custom_title"`
+}
+
+type ChatMemberAdministrator struct {
+	Status      string `json:"status"`
+	User        *User  `json:"user"`
+	CanBeEdited bool   `json:"can_be_edited"`
+	CustomTitle string `json:"custom_title"`
+	ChatAdministratorRights
+}
+
+type ChatMemberMember struct {
+	Status    string `json:"status"`
+	User      *User  `json:"user"`
+	UntilDate int64  `json:"until_date"`
+}
+
+type ChatMemberRestricted struct {
+	Status    string `json:"status"`
+	User      *User  `json:"user"`
+	IsMember  bool   `json:"is_member"`
+	UntilDate int64  `json:"until_date"`
+	ChatPermissions
+}
+
+type ChatMemberLeft struct {
+	Status string `json:"status"`
+	User   *User  `json:"user"`
+}
+
+type ChatMemberBanned struct {
+	Status    string `json:"status"`
+	User      *User  `json:"user"`
+	UntilDate int64  `json:"until_date"`
+}
+
+// ChatMemberUnknown holds a status this version does not know yet, so a new
+// Bot API status does not break decoding of the whole update.
+type ChatMemberUnknown struct {
+	Status string          `json:"status"`
+	User   *User           `json:"user"`
+	Raw    json.RawMessage `json:"-"`
+}
+
+func (m ChatMemberOwner) MemberStatus() string         { return ChatMemberStatusOwner }
+func (m ChatMemberAdministrator) MemberStatus() string { return ChatMemberStatusAdministrator }
+func (m ChatMemberMember) MemberStatus() string        { return ChatMemberStatusMember }
+func (m ChatMemberRestricted) MemberStatus() string    { return ChatMemberStatusRestricted }
+func (m ChatMemberLeft) MemberStatus() string          { return ChatMemberStatusLeft }
+func (m ChatMemberBanned) MemberStatus() string        { return ChatMemberStatusBanned }
+func (m ChatMemberUnknown) MemberStatus() string       { return m.Status }
+
+func (m ChatMemberOwner) MemberUser() *User         { return m.User }
+func (m ChatMemberAdministrator) MemberUser() *User { return m.User }
+func (m ChatMemberMember) MemberUser() *User        { return m.User }
+func (m ChatMemberRestricted) MemberUser() *User    { return m.User }
+func (m ChatMemberLeft) MemberUser() *User          { return m.User }
+func (m ChatMemberBanned) MemberUser() *User        { return m.User }
+func (m ChatMemberUnknown) MemberUser() *User       { return m.User }
+
+func decodeChatMember(raw json.RawMessage) (ChatMember, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var header struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, err
+	}
+
+	var member ChatMember
+	var err error
+	switch header.Status {
+	case ChatMemberStatusOwner:
+		var m ChatMemberOwner
+		err = json.Unmarshal(raw, &m)
+		member = m
+	case ChatMemberStatusAdministrator:
+		var m ChatMemberAdministrator
+		err = json.Unmarshal(raw, &m)
+		member = m
+	case ChatMemberStatusMember:
+		var m ChatMemberMember
+		err = json.Unmarshal(raw, &m)
+		member = m
+	case ChatMemberStatusRestricted:
+		var m ChatMemberRestricted
+		err = json.Unmarshal(raw, &m)
+		member = m
+	case ChatMemberStatusLeft:
+		var m ChatMemberLeft
+		err = json.Unmarshal(raw, &m)
+		member = m
+	case ChatMemberStatusBanned:
+		var m ChatMemberBanned
+		err = json.Unmarshal(raw, &m)
+		member = m
+	default:
+		m := ChatMemberUnknown{Raw: append(json.RawMessage{}, raw...)}
+		err = json.Unmarshal(raw, &m)
+		member = m
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (b *Bot) BanChatMember(chatID int64, userID int64, untilDate int64, revokeMessages bool) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	if untilDate != 0 {
+		message["until_date"] = untilDate
+	}
+	if revokeMessages {
+		message["revoke_messages"] = true
+	}
+
+	return b.callMethod("banChatMember", message, nil)
+}
+
+func (b *Bot) UnbanChatMember(chatID int64, userID int64, onlyIfBanned bool) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	if onlyIfBanned {
+		message["only_if_banned"] = true
+	}
+
+	return b.callMethod("unbanChatMember", message, nil)
+}
+
+func (b *Bot) RestrictChatMember(chatID int64, userID int64, permissions ChatPermissions, untilDate int64) error {
+	message := map[string]interface{}{
+		"chat_id":                          chatID,
+		"user_id":                          userID,
+		"permissions":                      permissions,
+		"use_independent_chat_permissions": true,
+	}
+
+	if untilDate != 0 {
+		message["until_date"] = untilDate
+	}
+
+	return b.callMethod("restrictChatMember", message, nil)
+}
+
+func (b *Bot) PromoteChatMember(chatID int64, userID int64, rights ChatAdministratorRights) error {
+	rightsJSON, err := json.Marshal(rights)
+	if err != nil {
+		return err
+	}
+
+	message := map[string]interface{}{}
+	if err := json.Unmarshal(rightsJSON, &message); err != nil {
+		return err
+	}
+	message["chat_id"] = chatID
+	message["user_id"] = userID
+
+	return b.callMethod("promoteChatMember", message, nil)
+}
+
+func (b *Bot) SetChatAdministratorCustomTitle(chatID int64, userID int64, customTitle string) error {
+	message := map[string]interface{}{
+		"chat_id":      chatID,
+		"user_id":      userID,
+		"custom_title": customTitle,
+	}
+
+	return b.callMethod("setChatAdministratorCustomTitle", message, nil)
+}
+
+func (b *Bot) SetChatPermissions(chatID int64, permissions ChatPermissions) error {
+	message := map[string]interface{}{
+		"chat_id":                          chatID,
+		"permissions":                      permissions,
+		"use_independent_chat_permissions": true,
+	}
+
+	return b.callMethod("setChatPermissions", message, nil)
+}
+
+func (b *Bot) GetChatAdministrators(chatID int64) ([]ChatMember, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	var result []json.RawMessage
+	if err := b.callMethod("getChatAdministrators", message, &result); err != nil {
+		return nil, err
+	}
+
+	members := make([]ChatMember, 0, len(result))
+	for _, raw := range result {
+		member, err := decodeChatMember(raw)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (b *Bot) GetChatMember(chatID int64, userID int64) (ChatMember, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	var result json.RawMessage
+	if err := b.callMethod("getChatMember", message, &result); err != nil {
+		return nil, err
+	}
+	return decodeChatMember(result)
+}
+
+func (b *Bot) GetChatMemberCount(chatID int64) (int, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	var result int
+	if err := b.callMethod("getChatMemberCount", message, &result); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (b *Bot) LeaveChat(chatID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	return b.callMethod("leaveChat", message, nil)
+}
+
+type ChatMemberUpdated struct {
+	Chat          *Chat           `json:"chat"`
+	From          *User           `json:"from"`
+	Date          int64           `json:"date"`
+	OldChatMember ChatMember      `json:"old_chat_member"`
+	NewChatMember ChatMember      `json:"new_chat_member"`
+	InviteLink    *ChatInviteLink `json:"invite_link"`
+}
+
+func (u *ChatMemberUpdated) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Chat          *Chat           `json:"chat"`
+		From          *User           `json:"from"`
+		Date          int64           `json:"date"`
+		OldChatMember json.RawMessage `json:"old_chat_member"`
+		NewChatMember json.RawMessage `json:"new_chat_member"`
+		InviteLink    *ChatInviteLink `json:"invite_link"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// a malformed member must not fail the whole getUpdates batch
+	oldMember, err := decodeChatMember(raw.OldChatMember)
+	if err != nil {
+		log.Println("Error decoding old_chat_member:", err)
+	}
+	newMember, err := decodeChatMember(raw.NewChatMember)
+	if err != nil {
+		log.Println("Error decoding new_chat_member:", err)
+	}
+
+	u.Chat = raw.Chat
+	u.From = raw.From
+	u.Date = raw.Date
+	u.OldChatMember = oldMember
+	u.NewChatMember = newMember
+	u.InviteLink = raw.InviteLink
+	return nil
+}
+
+func (u *ChatMemberUpdated) Joined() bool {
+	return !isChatMember(u.OldChatMember) && isChatMember(u.NewChatMember)
+}
+
+func isChatMember(member ChatMember) bool {
+	switch m := member.(type) {
+	case ChatMemberOwner, ChatMemberAdministrator, ChatMemberMember:
+		return true
+	case ChatMemberRestricted:
+		return m.IsMember
+	}
+	return false
+}
+
+type FilterNewChatMembers struct {
+	ChatID int64
+}
+
+func (f FilterNewChatMembers) Match(update Update) bool {
+	chatID, users := newChatMembers(update)
+	if len(users) == 0 {
+		return false
+	}
+	return f.ChatID == 0 || chatID == f.ChatID
+}
+
+func newChatMembers(update Update) (int64, []User) {
+	if update.Message != nil && update.Message.Chat != nil && len(update.Message.NewChatMembers) > 0 {
+		return update.Message.Chat.ID, update.Message.NewChatMembers
+	}
+	if update.ChatMember != nil && update.ChatMember.Chat != nil && update.ChatMember.Joined() {
+		if user := update.ChatMember.NewChatMember.MemberUser(); user != nil {
+			return update.ChatMember.Chat.ID, []User{*user}
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,105 @@
+package LCB
+
+type ChatPhoto struct {
+	SmallFileID       string `json:"small_file_id"`
+	SmallFileUniqueID string `json:"small_file_unique_id"`
+	BigFileID         string `json:"big_file_id"`
+	BigFileUniqueID   string `json:"big_file_unique_id"`
+}
+
+type ChatLocation struct {
+	Location *Location `json:"location"`
+	Address  string    `json:"address"`
+}
+
+type ChatFullInfo struct {
+	Chat
+	AccentColorID                      int              `json:"accent_color_id"`
+	MaxReactionCount                   int              `json:"max_reaction_count"`
+	Photo                              *ChatPhoto       `json:"photo"`
+	ActiveUsernames                    []string         `json:"active_usernames"`
+	Bio                                string           `json:"bio"`
+	HasPrivateForwards                 bool             `json:"has_private_forwards"`
+	HasRestrictedVoiceAndVideoMessages bool             `json:"has_restricted_voice_and_video_messages"`
+	JoinToSendMessages                 bool             `json:"join_to_send_messages"`
+	JoinByRequest                      bool             `json:"join_by_request"`
+	Description                        string           `json:"description"`
+	InviteLink                         string           `json:"invite_link"`
+	PinnedMessage                      *Message         `json:"pinned_message"`
+	Permissions                        *ChatPermissions `json:"permissions"`
+	SlowModeDelay                      int              `json:"slow_mode_delay"`
+	UnrestrictBoostCount               int              `json:"unrestrict_boost_count"`
+	MessageAutoDeleteTime              int              `json:"message_auto_delete_time"`
+	HasAggressiveAntiSpamEnabled       bool             `json:"has_aggressive_anti_spam_enabled"`
+	HasHiddenMembers                   bool             `json:"has_hidden_members"`
+	HasProtectedContent                bool             `json:"has_protected_content"`
+	HasVisibleHistory                  bool             `json:"has_visible_history"`
+	StickerSetName                     string           `json:"sticker_set_name"`
+	CanSetStickerSet                   bool             `json:"can_set_sticker_set"`
+	CustomEmojiStickerSetName          string           `json:"custom_emoji_sticker_set_name"`
+	LinkedChatID                       int64            `json:"linked_chat_id"`
+	Location                           *ChatLocation    `json:"location"`
+}
+
+func (b *Bot) GetChat(chatID int64) (*ChatFullInfo, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	var result ChatFullInfo
+	if err := b.callMethod("getChat", message, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (b *Bot) SetChatTitle(chatID int64, title string) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"title":   title,
+	}
+
+	return b.callMethod("setChatTitle", message, nil)
+}
+
+func (b *Bot) SetChatDescription(chatID int64, description string) error {
+	message := map[string]interface{}{
+		"chat_id":     chatID,
+		"description": description,
+	}
+
+	return b.callMethod("setChatDescription", message, nil)
+}
+
+func (b *Bot) SetChatPhoto(chatID int64, photoPath string) error {
+	fields := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	return b.callMultipart("setChatPhoto", fields, "photo", photoPath, nil)
+}
+
+func (b *Bot) DeleteChatPhoto(chatID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	return b.callMethod("deleteChatPhoto", message, nil)
+}
+
+func (b *Bot) SetChatStickerSet(chatID int64, stickerSetName string) error {
+	message := map[string]interface{}{
+		"chat_id":          chatID,
+		"sticker_set_name": stickerSetName,
+	}
+
+	return b.callMethod("setChatStickerSet", message, nil)
+}
+
+func (b *Bot) DeleteChatStickerSet(chatID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	return b.callMethod("deleteChatStickerSet", message, nil)
+}
@@ -0,0 +1,339 @@
+package LCB
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+)
+
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+type BotCommandScope struct {
+	Type   string `json:"type"`
+	ChatID int64  `json:"chat_id,omitempty"`
+	UserID int64  `json:"user_id,omitempty"`
+}
+
+func ScopeDefault() *BotCommandScope {
+	return &BotCommandScope{Type: "default"}
+}
+
+func ScopeAllPrivateChats() *BotCommandScope {
+	return &BotCommandScope{Type: "all_private_chats"}
+}
+
+func ScopeAllGroupChats() *BotCommandScope {
+	return &BotCommandScope{Type: "all_group_chats"}
+}
+
+func ScopeAllChatAdministrators() *BotCommandScope {
+	return &BotCommandScope{Type: "all_chat_administrators"}
+}
+
+func ScopeChat(chatID int64) *BotCommandScope {
+	return &BotCommandScope{Type: "chat", ChatID: chatID}
+}
+
+func ScopeChatAdministrators(chatID int64) *BotCommandScope {
+	return &BotCommandScope{Type: "chat_administrators", ChatID: chatID}
+}
+
+func ScopeChatMember(chatID int64, userID int64) *BotCommandScope {
+	return &BotCommandScope{Type: "chat_member", ChatID: chatID, UserID: userID}
+}
+
+type ChatMenuButton struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	WebApp *WebAppInfo `json:"web_app,omitempty"`
+}
+
+func MenuButtonCommands() ChatMenuButton {
+	return ChatMenuButton{Type: "commands"}
+}
+
+func MenuButtonWebApp(text string, url string) ChatMenuButton {
+	return ChatMenuButton{Type: "web_app", Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+func MenuButtonDefault() ChatMenuButton {
+	return ChatMenuButton{Type: "default"}
+}
+
+type Command struct {
+	Command      string
+	Description  string
+	Descriptions map[string]string
+	Scopes       []*BotCommandScope
+}
+
+// FilterCommand matches /command and /command@username. The mention must be
+// the bot's own username: Username if set, otherwise the one reported by
+// getMe for filters registered with AddHandler or AddCommand.
+type FilterCommand struct {
+	Command  string
+	Username string
+	bot      *Bot
+}
+
+func (f FilterCommand) Match(update Update) bool {
+	if update.Message == nil || update.Message.Text == nil {
+		return false
+	}
+	fields := strings.Fields(*update.Message.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return false
+	}
+	name := fields[0]
+	if at := strings.Index(name, "@"); at >= 0 {
+		if username := f.username(); username != "" && !strings.EqualFold(name[at+1:], username) {
+			return false
+		}
+		name = name[:at]
+	}
+	return strings.TrimPrefix(name, "/") == strings.TrimPrefix(f.Command, "/")
+}
+
+func (f FilterCommand) username() string {
+	if f.Username != "" || f.bot == nil {
+		return f.Username
+	}
+	return f.bot.Username()
+}
+
+func CommandArgs(update Update) string {
+	if update.Message == nil || update.Message.Text == nil {
+		return ""
+	}
+	parts := strings.SplitN(strings.TrimSpace(*update.Message.Text), " ", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
+func (b *Bot) AddCommand(command Command, callback func(update Update)) {
+	command.Command = strings.TrimPrefix(command.Command, "/")
+	b.commands = append(b.commands, command)
+	b.AddHandler(FilterCommand{Command: command.Command}, callback)
+}
+
+type commandTarget struct {
+	scope    *BotCommandScope
+	language string
+}
+
+// broadCommandScopes are cleared on every sync when no registered command
+// uses them, so commands removed since the last deploy do not linger.
+var broadCommandScopes = []*BotCommandScope{
+	ScopeDefault(),
+	ScopeAllPrivateChats(),
+	ScopeAllGroupChats(),
+	ScopeAllChatAdministrators(),
+}
+
+// SyncCommands sets the command list of every scope and language used by the
+// registered commands, and deletes the lists of broad scopes and of pairs
+// synced earlier by this process that are no longer used. Lists left in chat
+// scopes or languages by a previous process must be removed with
+// DeleteMyCommands.
+func (b *Bot) SyncCommands() error {
+	scopes := map[string]*BotCommandScope{}
+	scopeCommands := map[string][]Command{}
+	scopeOrder := []string{}
+	languages := map[string]bool{"": true}
+
+	for _, command := range b.commands {
+		commandScopes := command.Scopes
+		if len(commandScopes) == 0 {
+			commandScopes = []*BotCommandScope{ScopeDefault()}
+		}
+		for _, scope := range commandScopes {
+			keyJSON, err := json.Marshal(scope)
+			if err != nil {
+				return err
+			}
+			key := string(keyJSON)
+			if _, ok := scopes[key]; !ok {
+				scopes[key] = scope
+				scopeOrder = append(scopeOrder, key)
+			}
+			scopeCommands[key] = append(scopeCommands[key], command)
+		}
+		for language := range command.Descriptions {
+			languages[language] = true
+		}
+	}
+
+	languageList := make([]string, 0, len(languages))
+	for language := range languages {
+		languageList = append(languageList, language)
+	}
+	sort.Strings(languageList)
+
+	synced := map[string]commandTarget{}
+	for _, key := range scopeOrder {
+		for _, language := range languageList {
+			synced[key+"|"+language] = commandTarget{scopes[key], language}
+		}
+	}
+	stale := map[string]commandTarget{}
+	for key, target := range b.syncedCommands {
+		stale[key] = target
+	}
+	for _, scope := range broadCommandScopes {
+		keyJSON, err := json.Marshal(scope)
+		if err != nil {
+			return err
+		}
+		for _, language := range languageList {
+			stale[string(keyJSON)+"|"+language] = commandTarget{scope, language}
+		}
+	}
+	staleKeys := make([]string, 0, len(stale))
+	for key := range stale {
+		if _, ok := synced[key]; !ok {
+			staleKeys = append(staleKeys, key)
+		}
+	}
+	sort.Strings(staleKeys)
+	for _, key := range staleKeys {
+		if err := b.DeleteMyCommands(stale[key].scope, stale[key].language); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range scopeOrder {
+		for _, language := range languageList {
+			commands := []BotCommand{}
+			for _, command := range scopeCommands[key] {
+				description := command.Description
+				if translated, ok := command.Descriptions[language]; ok && language != "" {
+					description = translated
+				}
+				commands = append(commands, BotCommand{Command: command.Command, Description: description})
+			}
+			if err := b.SetMyCommands(commands, scopes[key], language); err != nil {
+				return err
+			}
+		}
+	}
+	b.syncedCommands = synced
+	return nil
+}
+
+func (b *Bot) syncCommandsOnStart() {
+	if len(b.commands) == 0 {
+		return
+	}
+	if err := b.SyncCommands(); err != nil {
+		log.Println("Error syncing commands:", err)
+	}
+}
+
+// cacheUsername looks up the bot's username before updates arrive, so that
+// FilterCommand does not call getMe from the update loop.
+func (b *Bot) cacheUsername() {
+	for _, handler := range b.handlers {
+		if _, ok := handler.Filter.(FilterCommand); ok {
+			b.Username()
+			return
+		}
+	}
+}
+
+func commandScopeMessage(scope *BotCommandScope, languageCode string) map[string]interface{} {
+	message := map[string]interface{}{}
+	if scope != nil {
+		message["scope"] = scope
+	}
+	if languageCode != "" {
+		message["language_code"] = languageCode
+	}
+	return message
+}
+
+func (b *Bot) SetMyCommands(commands []BotCommand, scope *BotCommandScope, languageCode string) error {
+	message := commandScopeMessage(scope, languageCode)
+	message["commands"] = commands
+
+	return b.callMethod("setMyCommands", message, nil)
+}
+
+func (b *Bot) GetMyCommands(scope *BotCommandScope, languageCode string) ([]BotCommand, error) {
+	var result []BotCommand
+	if err := b.callMethod("getMyCommands", commandScopeMessage(scope, languageCode), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (b *Bot) DeleteMyCommands(scope *BotCommandScope, languageCode string) error {
+	return b.callMethod("deleteMyCommands", commandScopeMessage(scope, languageCode), nil)
+}
+
+// GetMe fetches the bot's own user and caches it for Username.
+func (b *Bot) GetMe() (*User, error) {
+	var me User
+	if err := b.callMethod("getMe", map[string]interface{}{}, &me); err != nil {
+		return nil, err
+	}
+	b.meMu.Lock()
+	b.me = &me
+	b.meMu.Unlock()
+	return &me, nil
+}
+
+// Username returns the bot's username, calling getMe on first use. It
+// returns "" if getMe fails; the next call tries again.
+func (b *Bot) Username() string {
+	b.meMu.Lock()
+	me := b.me
+	b.meMu.Unlock()
+	if me == nil {
+		var err error
+		if me, err = b.GetMe(); err != nil {
+			log.Println("Error getting bot username:", err)
+			return ""
+		}
+	}
+	return me.Username
+}
+
+func (b *Bot) SetMyName(name string, languageCode string) error {
+	message := commandScopeMessage(nil, languageCode)
+	message["name"] = name
+
+	return b.callMethod("setMyName", message, nil)
+}
+
+func (b *Bot) SetMyDescription(description string, languageCode string) error {
+	message := commandScopeMessage(nil, languageCode)
+	message["description"] = description
+
+	return b.callMethod("setMyDescription", message, nil)
+}
+
+func (b *Bot) SetMyShortDescription(shortDescription string, languageCode string) error {
+	message := commandScopeMessage(nil, languageCode)
+	message["short_description"] = shortDescription
+
+	return b.callMethod("setMyShortDescription", message, nil)
+}
+
+// A zero chatID changes the default menu button for all private chats.
+func (b *Bot) SetChatMenuButton(chatID int64, button ChatMenuButton) error {
+	message := map[string]interface{}{
+		"menu_button": button,
+	}
+
+	if chatID != 0 {
+		message["chat_id"] = chatID
+	}
+
+	return b.callMethod("setChatMenuButton", message, nil)
+}
@@ -0,0 +1,169 @@
+package LCB
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a standard 5-field cron expression:
+// minute hour day-of-month month day-of-week.
+type CronSchedule struct {
+	minute  [60]bool
+	hour    [24]bool
+	day     [32]bool
+	month   [13]bool
+	weekday [7]bool
+	anyDay  bool
+	anyWeek bool
+}
+
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func ParseCron(spec string) (*CronSchedule, error) {
+	if alias, ok := cronAliases[strings.TrimSpace(spec)]; ok {
+		spec = alias
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	schedule := &CronSchedule{
+		anyDay:  fields[2] == "*",
+		anyWeek: fields[4] == "*",
+	}
+	if err := parseCronField(fields[0], 0, 59, schedule.minute[:]); err != nil {
+		return nil, fmt.Errorf("cron %q minute: %w", spec, err)
+	}
+	if err := parseCronField(fields[1], 0, 23, schedule.hour[:]); err != nil {
+		return nil, fmt.Errorf("cron %q hour: %w", spec, err)
+	}
+	if err := parseCronField(fields[2], 1, 31, schedule.day[:]); err != nil {
+		return nil, fmt.Errorf("cron %q day of month: %w", spec, err)
+	}
+	if err := parseCronField(fields[3], 1, 12, schedule.month[:]); err != nil {
+		return nil, fmt.Errorf("cron %q month: %w", spec, err)
+	}
+	var weekday [8]bool
+	if err := parseCronField(fields[4], 0, 7, weekday[:]); err != nil {
+		return nil, fmt.Errorf("cron %q day of week: %w", spec, err)
+	}
+	copy(schedule.weekday[:], weekday[:7])
+	if weekday[7] {
+		schedule.weekday[0] = true
+	}
+	return schedule, nil
+}
+
+func parseCronField(field string, min int, max int, values []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		from, to := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return fmt.Errorf("invalid range %q", part)
+			}
+			if to, err = strconv.Atoi(bounds[1]); err != nil {
+				return fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", part)
+			}
+			from, to = n, n
+			if step > 1 {
+				to = max
+			}
+		}
+
+		if from < min || to > max || from > to {
+			return fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for v := from; v <= to; v += step {
+			values[v] = true
+		}
+	}
+	return nil
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	dayMatch := c.day[t.Day()]
+	weekMatch := c.weekday[int(t.Weekday())]
+	switch {
+	case c.anyDay && c.anyWeek:
+		return true
+	case c.anyDay:
+		return weekMatch
+	case c.anyWeek:
+		return dayMatch
+	}
+	return dayMatch || weekMatch
+}
+
+// Next returns the first matching minute strictly after t, or the zero time
+// if none exists within five years. Times are wall-clock times in t's
+// location: a time skipped when clocks spring forward does not run that day,
+// and a time repeated when they fall back runs only once.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	after := wallClock(t)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location()))
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()))
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location()))
+			continue
+		}
+		if !c.minute[t.Minute()] || !wallClock(t).After(after) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// forward returns next, moved past t if it lies in a gap where clocks spring
+// forward: time.Date resolves such a wall time to an earlier instant, which
+// would send Next back in time.
+func forward(t time.Time, next time.Time) time.Time {
+	for !next.After(t) {
+		next = next.Add(time.Hour)
+	}
+	return next
+}
+
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
@@ -0,0 +1,140 @@
+package LCB
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"1-x * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@reboot",
+	}
+	for _, spec := range specs {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", utc(2024, 1, 1, 10, 0, 30), utc(2024, 1, 1, 10, 1, 0)},
+		{"strictly after", "30 10 * * *", utc(2024, 1, 1, 10, 30, 0), utc(2024, 1, 2, 10, 30, 0)},
+		{"same minute later", "30 10 * * *", utc(2024, 1, 1, 10, 29, 59), utc(2024, 1, 1, 10, 30, 0)},
+		{"step", "*/15 * * * *", utc(2024, 1, 1, 10, 7, 0), utc(2024, 1, 1, 10, 15, 0)},
+		{"step wraps hour", "*/15 * * * *", utc(2024, 1, 1, 10, 45, 0), utc(2024, 1, 1, 11, 0, 0)},
+		{"step from value", "5/20 * * * *", utc(2024, 1, 1, 10, 30, 0), utc(2024, 1, 1, 10, 45, 0)},
+		{"step from value wraps", "5/20 * * * *", utc(2024, 1, 1, 10, 50, 0), utc(2024, 1, 1, 11, 5, 0)},
+		{"range with step", "0 9-17/4 * * *", utc(2024, 1, 1, 10, 0, 0), utc(2024, 1, 1, 13, 0, 0)},
+		{"range end", "0 9-17/4 * * *", utc(2024, 1, 1, 17, 0, 0), utc(2024, 1, 2, 9, 0, 0)},
+		{"range", "0 0 * * 1-5", utc(2024, 1, 5, 12, 0, 0), utc(2024, 1, 8, 0, 0, 0)},
+		{"list", "0 0 1,15 * *", utc(2024, 1, 2, 0, 0, 0), utc(2024, 1, 15, 0, 0, 0)},
+		{"list and range", "0 8,12-13 * * *", utc(2024, 1, 1, 8, 30, 0), utc(2024, 1, 1, 12, 0, 0)},
+		{"day of month or week", "0 0 13 * 5", utc(2024, 1, 1, 0, 0, 0), utc(2024, 1, 5, 0, 0, 0)},
+		{"day of month or week, month day first", "0 0 13 * 5", utc(2024, 9, 7, 0, 0, 0), utc(2024, 9, 13, 0, 0, 0)},
+		{"sunday as 0", "0 0 * * 0", utc(2024, 1, 1, 0, 0, 0), utc(2024, 1, 7, 0, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", utc(2024, 1, 1, 0, 0, 0), utc(2024, 1, 7, 0, 0, 0)},
+		{"month", "0 0 1 3 *", utc(2024, 3, 1, 0, 0, 0), utc(2025, 3, 1, 0, 0, 0)},
+		{"year wrap", "0 0 1 1 *", utc(2024, 12, 31, 23, 59, 0), utc(2025, 1, 1, 0, 0, 0)},
+		{"31st skips short months", "0 0 31 * *", utc(2024, 4, 1, 0, 0, 0), utc(2024, 5, 31, 0, 0, 0)},
+		{"feb 29", "0 0 29 2 *", utc(2024, 3, 1, 0, 0, 0), utc(2028, 2, 29, 0, 0, 0)},
+		{"feb 29 in leap year", "0 0 29 2 *", utc(2024, 1, 1, 0, 0, 0), utc(2024, 2, 29, 0, 0, 0)},
+		{"impossible date", "0 0 30 2 *", utc(2024, 1, 1, 0, 0, 0), time.Time{}},
+		{"hourly alias", "@hourly", utc(2024, 1, 1, 10, 0, 0), utc(2024, 1, 1, 11, 0, 0)},
+		{"weekly alias", "@weekly", utc(2024, 1, 1, 0, 0, 0), utc(2024, 1, 7, 0, 0, 0)},
+		{"monthly alias", "@monthly", utc(2024, 1, 31, 12, 0, 0), utc(2024, 2, 1, 0, 0, 0)},
+		{"yearly alias", "@yearly", utc(2024, 6, 1, 0, 0, 0), utc(2025, 1, 1, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNextMidnightGap(t *testing.T) {
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-09-08 00:00 does not exist in Santiago, clocks go to 01:00
+	schedule, err := ParseCron("0 12 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2024, 9, 7, 13, 0, 0, 0, santiago)
+	want := time.Date(2024, 9, 8, 12, 0, 0, 0, santiago)
+	if got := schedule.Next(from); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want %s", from, got, want)
+	}
+}
+
+func TestCronNextDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, newYork)
+	}
+	// 2024-03-10 02:00 EST jumps to 03:00 EDT; 2024-11-03 02:00 EDT falls
+	// back to 01:00 EST, so 01:00-01:59 happens twice. Ambiguous times are
+	// given in UTC: EDT is UTC-4, EST is UTC-5.
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"skipped time does not run", "30 2 * * *", local(3, 9, 3, 0), local(3, 11, 2, 30)},
+		{"hourly across spring forward", "0 * * * *", local(3, 10, 1, 0), local(3, 10, 3, 0)},
+		{"daily after spring forward", "0 9 * * *", local(3, 9, 9, 0), local(3, 10, 9, 0)},
+		{"repeated time runs first", "30 1 * * *", local(11, 3, 0, 45), time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC)},
+		{"repeated time runs once", "30 1 * * *", time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC).In(newYork), local(11, 4, 1, 30)},
+		{"hourly across fall back", "0 * * * *", time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC).In(newYork), time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC)},
+		{"after repeated hour", "0 3 * * *", local(11, 3, 0, 0), time.Date(2024, 11, 3, 8, 0, 0, 0, time.UTC)},
+		{"daily after fall back", "0 9 * * *", local(11, 2, 9, 0), local(11, 3, 9, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want.In(newYork))
+			}
+		})
+	}
+}
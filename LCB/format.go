@@ -0,0 +1,239 @@
+package LCB
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ParseModeHTML       = "HTML"
+	ParseModeMarkdownV2 = "MarkdownV2"
+)
+
+type MessageEntity struct {
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	URL      string `json:"url,omitempty"`
+	User     *User  `json:"user,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type Text struct {
+	parts []textPart
+}
+
+type textPart struct {
+	kind     string
+	text     string
+	url      string
+	language string
+	userID   int64
+}
+
+func NewText() *Text {
+	return &Text{}
+}
+
+func (t *Text) add(part textPart) *Text {
+	t.parts = append(t.parts, part)
+	return t
+}
+
+func (t *Text) Plain(text string) *Text {
+	return t.add(textPart{kind: "", text: text})
+}
+
+func (t *Text) Bold(text string) *Text {
+	return t.add(textPart{kind: "bold", text: text})
+}
+
+func (t *Text) Italic(text string) *Text {
+	return t.add(textPart{kind: "italic", text: text})
+}
+
+func (t *Text) Underline(text string) *Text {
+	return t.add(textPart{kind: "underline", text: text})
+}
+
+func (t *Text) Strikethrough(text string) *Text {
+	return t.add(textPart{kind: "strikethrough", text: text})
+}
+
+func (t *Text) Spoiler(text string) *Text {
+	return t.add(textPart{kind: "spoiler", text: text})
+}
+
+func (t *Text) Code(text string) *Text {
+	return t.add(textPart{kind: "code", text: text})
+}
+
+func (t *Text) Pre(text string, language string) *Text {
+	return t.add(textPart{kind: "pre", text: text, language: language})
+}
+
+func (t *Text) Link(text string, url string) *Text {
+	return t.add(textPart{kind: "text_link", text: text, url: url})
+}
+
+func (t *Text) Mention(text string, userID int64) *Text {
+	return t.add(textPart{kind: "text_mention", text: text, userID: userID})
+}
+
+func (t *Text) Blockquote(text string) *Text {
+	return t.add(textPart{kind: "blockquote", text: text})
+}
+
+func (t *Text) Line() *Text {
+	return t.Plain("\n")
+}
+
+func (t *Text) HTML() string {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		text := EscapeHTML(part.text)
+		switch part.kind {
+		case "bold":
+			sb.WriteString("<b>" + text + "</b>")
+		case "italic":
+			sb.WriteString("<i>" + text + "</i>")
+		case "underline":
+			sb.WriteString("<u>" + text + "</u>")
+		case "strikethrough":
+			sb.WriteString("<s>" + text + "</s>")
+		case "spoiler":
+			sb.WriteString("<tg-spoiler>" + text + "</tg-spoiler>")
+		case "code":
+			sb.WriteString("<code>" + text + "</code>")
+		case "pre":
+			if part.language != "" {
+				sb.WriteString(`<pre><code class="language-` + EscapeHTML(part.language) + `">` + text + "</code></pre>")
+			} else {
+				sb.WriteString("<pre>" + text + "</pre>")
+			}
+		case "text_link":
+			sb.WriteString(`<a href="` + EscapeHTML(part.url) + `">` + text + "</a>")
+		case "text_mention":
+			sb.WriteString(fmt.Sprintf(`<a href="tg://user?id=%d">%s</a>`, part.userID, text))
+		case "blockquote":
+			sb.WriteString("<blockquote>" + text + "</blockquote>")
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+func (t *Text) MarkdownV2() string {
+	var sb strings.Builder
+	for i, part := range t.parts {
+		switch part.kind {
+		case "bold":
+			sb.WriteString("*" + EscapeMarkdownV2(part.text) + "*")
+		case "italic":
+			sb.WriteString("_" + EscapeMarkdownV2(part.text) + "_")
+		case "underline":
+			sb.WriteString("__" + EscapeMarkdownV2(part.text) + "__")
+		case "strikethrough":
+			sb.WriteString("~" + EscapeMarkdownV2(part.text) + "~")
+		case "spoiler":
+			sb.WriteString("||" + EscapeMarkdownV2(part.text) + "||")
+		case "code":
+			sb.WriteString("`" + escapeMarkdownV2Code(part.text) + "`")
+		case "pre":
+			sb.WriteString("```" + part.language + "\n" + escapeMarkdownV2Code(part.text) + "\n```")
+		case "text_link":
+			sb.WriteString("[" + EscapeMarkdownV2(part.text) + "](" + escapeMarkdownV2URL(part.url) + ")")
+		case "text_mention":
+			sb.WriteString(fmt.Sprintf("[%s](tg://user?id=%d)", EscapeMarkdownV2(part.text), part.userID))
+		case "blockquote":
+			// A quote only starts at the beginning of a line and runs to its end.
+			if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+				sb.WriteString("\n")
+			}
+			lines := strings.Split(part.text, "\n")
+			for j, line := range lines {
+				lines[j] = ">" + EscapeMarkdownV2(line)
+			}
+			sb.WriteString(strings.Join(lines, "\n"))
+			if i+1 < len(t.parts) {
+				if next := t.parts[i+1]; next.kind != "" || !strings.HasPrefix(next.text, "\n") {
+					sb.WriteString("\n")
+				}
+			}
+		default:
+			sb.WriteString(EscapeMarkdownV2(part.text))
+		}
+	}
+	return sb.String()
+}
+
+func (t *Text) Entities() (string, []MessageEntity) {
+	var sb strings.Builder
+	entities := []MessageEntity{}
+	offset := 0
+	for _, part := range t.parts {
+		length := utf16Len(part.text)
+		sb.WriteString(part.text)
+		if part.kind != "" && length > 0 {
+			entity := MessageEntity{
+				Type:     part.kind,
+				Offset:   offset,
+				Length:   length,
+				URL:      part.url,
+				Language: part.language,
+			}
+			if part.kind == "text_mention" {
+				entity.User = &User{ID: part.userID}
+			}
+			entities = append(entities, entity)
+		}
+		offset += length
+	}
+	return sb.String(), entities
+}
+
+func (t *Text) String() string {
+	text, _ := t.Entities()
+	return text
+}
+
+func EscapeHTML(text string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+	).Replace(text)
+}
+
+func EscapeMarkdownV2(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("_*[]()~`>#+-=|{}.!\\", r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func escapeMarkdownV2Code(text string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(text)
+}
+
+func escapeMarkdownV2URL(url string) string {
+	return strings.NewReplacer("\\", "\\\\", ")", "\\)").Replace(url)
+}
+
+func utf16Len(text string) int {
+	n := 0
+	for _, r := range text {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,128 @@
+package LCB
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextFormats(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         *Text
+		wantHTML     string
+		wantMarkdown string
+		wantPlain    string
+		wantEntities []MessageEntity
+	}{
+		{
+			name:         "plain is escaped",
+			text:         NewText().Plain("1 < 2 & a_b.c!"),
+			wantHTML:     "1 &lt; 2 &amp; a_b.c!",
+			wantMarkdown: `1 < 2 & a\_b\.c\!`,
+			wantPlain:    "1 < 2 & a_b.c!",
+			wantEntities: []MessageEntity{},
+		},
+		{
+			name:         "styles",
+			text:         NewText().Bold("b").Plain(" ").Italic("i").Plain(" ").Underline("u").Plain(" ").Strikethrough("s").Plain(" ").Spoiler("x"),
+			wantHTML:     "<b>b</b> <i>i</i> <u>u</u> <s>s</s> <tg-spoiler>x</tg-spoiler>",
+			wantMarkdown: "*b* _i_ __u__ ~s~ ||x||",
+			wantPlain:    "b i u s x",
+			wantEntities: []MessageEntity{
+				{Type: "bold", Offset: 0, Length: 1},
+				{Type: "italic", Offset: 2, Length: 1},
+				{Type: "underline", Offset: 4, Length: 1},
+				{Type: "strikethrough", Offset: 6, Length: 1},
+				{Type: "spoiler", Offset: 8, Length: 1},
+			},
+		},
+		{
+			name:         "code and pre",
+			text:         NewText().Code("a`b").Line().Pre("x := `y`", "go"),
+			wantHTML:     "<code>a`b</code>\n<pre><code class=\"language-go\">x := `y`</code></pre>",
+			wantMarkdown: "`a\\`b`\n```go\nx := \\`y\\`\n```",
+			wantPlain:    "a`b\nx := `y`",
+			wantEntities: []MessageEntity{
+				{Type: "code", Offset: 0, Length: 3},
+				{Type: "pre", Offset: 4, Length: 8, Language: "go"},
+			},
+		},
+		{
+			name:         "links",
+			text:         NewText().Link("site", "https://example.com/a_(b)").Plain(" ").Mention("Bob", 42),
+			wantHTML:     `<a href="https://example.com/a_(b)">site</a> <a href="tg://user?id=42">Bob</a>`,
+			wantMarkdown: `[site](https://example.com/a_(b\)) [Bob](tg://user?id=42)`,
+			wantPlain:    "site Bob",
+			wantEntities: []MessageEntity{
+				{Type: "text_link", Offset: 0, Length: 4, URL: "https://example.com/a_(b)"},
+				{Type: "text_mention", Offset: 5, Length: 3, User: &User{ID: 42}},
+			},
+		},
+		{
+			name:         "blockquote mid-line",
+			text:         NewText().Plain("Note: ").Blockquote("quoted").Plain("after"),
+			wantHTML:     "Note: <blockquote>quoted</blockquote>after",
+			wantMarkdown: "Note: \n>quoted\nafter",
+			wantPlain:    "Note: quotedafter",
+			wantEntities: []MessageEntity{
+				{Type: "blockquote", Offset: 6, Length: 6},
+			},
+		},
+		{
+			name:         "blockquote on its own lines",
+			text:         NewText().Plain("Note:").Line().Blockquote("one\ntwo.").Line().Plain("end"),
+			wantHTML:     "Note:\n<blockquote>one\ntwo.</blockquote>\nend",
+			wantMarkdown: "Note:\n>one\n>two\\.\nend",
+			wantPlain:    "Note:\none\ntwo.\nend",
+			wantEntities: []MessageEntity{
+				{Type: "blockquote", Offset: 6, Length: 8},
+			},
+		},
+		{
+			name:         "blockquote at the end",
+			text:         NewText().Blockquote("last"),
+			wantHTML:     "<blockquote>last</blockquote>",
+			wantMarkdown: ">last",
+			wantPlain:    "last",
+			wantEntities: []MessageEntity{
+				{Type: "blockquote", Offset: 0, Length: 4},
+			},
+		},
+		{
+			name:         "offsets count utf-16 units",
+			text:         NewText().Plain("🎉 é ").Bold("👍🏽 ok").Plain(" ").Italic("x"),
+			wantHTML:     "🎉 é <b>👍🏽 ok</b> <i>x</i>",
+			wantMarkdown: "🎉 é *👍🏽 ok* _x_",
+			wantPlain:    "🎉 é 👍🏽 ok x",
+			wantEntities: []MessageEntity{
+				{Type: "bold", Offset: 5, Length: 7},
+				{Type: "italic", Offset: 13, Length: 1},
+			},
+		},
+		{
+			name:         "empty styled part has no entity",
+			text:         NewText().Plain("a").Bold("").Plain("b"),
+			wantHTML:     "a<b></b>b",
+			wantMarkdown: "a**b",
+			wantPlain:    "ab",
+			wantEntities: []MessageEntity{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.text.HTML(); got != tt.wantHTML {
+				t.Errorf("HTML() = %q, want %q", got, tt.wantHTML)
+			}
+			if got := tt.text.MarkdownV2(); got != tt.wantMarkdown {
+				t.Errorf("MarkdownV2() = %q, want %q", got, tt.wantMarkdown)
+			}
+			plain, entities := tt.text.Entities()
+			if plain != tt.wantPlain {
+				t.Errorf("Entities() text = %q, want %q", plain, tt.wantPlain)
+			}
+			if !reflect.DeepEqual(entities, tt.wantEntities) {
+				t.Errorf("Entities() = %+v, want %+v", entities, tt.wantEntities)
+			}
+		})
+	}
+}
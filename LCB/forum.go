@@ -0,0 +1,151 @@
+package LCB
+
+const (
+	TopicColorBlue   = 0x6FB9F0
+	TopicColorYellow = 0xFFD67E
+	TopicColorViolet = 0xCB86DB
+	TopicColorGreen  = 0x8EEE98
+	TopicColorRose   = 0xFF93B2
+	TopicColorRed    = 0xFB6F5F
+)
+
+type ForumTopic struct {
+	MessageThreadID   int64  `json:"message_thread_id"`
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id"`
+}
+
+type ForumTopicCreated struct {
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id"`
+}
+
+type ForumTopicEdited struct {
+	Name              string `json:"name"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id"`
+}
+
+type ForumTopicClosed struct{}
+
+type ForumTopicReopened struct{}
+
+type FilterTopic struct {
+	ChatID   int64
+	ThreadID int64
+}
+
+func (f FilterTopic) Match(update Update) bool {
+	message := update.Message
+	if message == nil && update.CallbackQuery != nil {
+		message = update.CallbackQuery.Message
+	}
+	if message == nil || message.Chat == nil || !message.IsTopicMessage {
+		return false
+	}
+	if f.ChatID != 0 && message.Chat.ID != f.ChatID {
+		return false
+	}
+	return f.ThreadID == 0 || message.MessageThreadID == f.ThreadID
+}
+
+func (b *Bot) CreateForumTopic(chatID int64, name string, iconColor int, iconCustomEmojiID string) (*ForumTopic, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"name":    name,
+	}
+
+	if iconColor != 0 {
+		message["icon_color"] = iconColor
+	}
+	if iconCustomEmojiID != "" {
+		message["icon_custom_emoji_id"] = iconCustomEmojiID
+	}
+
+	var result ForumTopic
+	if err := b.callMethod("createForumTopic", message, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EditForumTopic keeps the icon when iconCustomEmojiID is nil; pointing it at
+// an empty string removes the icon.
+func (b *Bot) EditForumTopic(chatID int64, messageThreadID int64, name string, iconCustomEmojiID *string) error {
+	message := map[string]interface{}{
+		"chat_id":           chatID,
+		"message_thread_id": messageThreadID,
+	}
+
+	if name != "" {
+		message["name"] = name
+	}
+	if iconCustomEmojiID != nil {
+		message["icon_custom_emoji_id"] = *iconCustomEmojiID
+	}
+
+	return b.callMethod("editForumTopic", message, nil)
+}
+
+func (b *Bot) topicMethod(method string, chatID int64, messageThreadID int64) error {
+	message := map[string]interface{}{
+		"chat_id":           chatID,
+		"message_thread_id": messageThreadID,
+	}
+
+	return b.callMethod(method, message, nil)
+}
+
+func (b *Bot) CloseForumTopic(chatID int64, messageThreadID int64) error {
+	return b.topicMethod("closeForumTopic", chatID, messageThreadID)
+}
+
+func (b *Bot) ReopenForumTopic(chatID int64, messageThreadID int64) error {
+	return b.topicMethod("reopenForumTopic", chatID, messageThreadID)
+}
+
+func (b *Bot) DeleteForumTopic(chatID int64, messageThreadID int64) error {
+	return b.topicMethod("deleteForumTopic", chatID, messageThreadID)
+}
+
+func (b *Bot) UnpinAllForumTopicMessages(chatID int64, messageThreadID int64) error {
+	return b.topicMethod("unpinAllForumTopicMessages", chatID, messageThreadID)
+}
+
+func (b *Bot) EditGeneralForumTopic(chatID int64, name string) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"name":    name,
+	}
+
+	return b.callMethod("editGeneralForumTopic", message, nil)
+}
+
+func (b *Bot) generalTopicMethod(method string, chatID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	return b.callMethod(method, message, nil)
+}
+
+func (b *Bot) CloseGeneralForumTopic(chatID int64) error {
+	return b.generalTopicMethod("closeGeneralForumTopic", chatID)
+}
+
+func (b *Bot) ReopenGeneralForumTopic(chatID int64) error {
+	return b.generalTopicMethod("reopenGeneralForumTopic", chatID)
+}
+
+func (b *Bot) HideGeneralForumTopic(chatID int64) error {
+	return b.generalTopicMethod("hideGeneralForumTopic", chatID)
+}
+
+func (b *Bot) UnhideGeneralForumTopic(chatID int64) error {
+	return b.generalTopicMethod("unhideGeneralForumTopic", chatID)
+}
+
+func (b *Bot) UnpinAllGeneralForumTopicMessages(chatID int64) error {
+	return b.generalTopicMethod("unpinAllGeneralForumTopicMessages", chatID)
+}
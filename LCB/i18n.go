@@ -0,0 +1,337 @@
+package LCB
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Bundle struct {
+	DefaultLocale string
+	mu            sync.RWMutex
+	messages      map[string]map[string]interface{}
+}
+
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		DefaultLocale: defaultLocale,
+		messages:      make(map[string]map[string]interface{}),
+	}
+}
+
+func (bu *Bundle) AddMessages(locale string, messages map[string]interface{}) {
+	bu.mu.Lock()
+	defer bu.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	if bu.messages[locale] == nil {
+		bu.messages[locale] = make(map[string]interface{})
+	}
+	flattenMessages(bu.messages[locale], "", messages)
+}
+
+// LoadData decodes a locale file with any unmarshal function, e.g. yaml.Unmarshal.
+func (bu *Bundle) LoadData(locale string, data []byte, unmarshal func([]byte, interface{}) error) error {
+	var messages map[string]interface{}
+	if err := unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("locale %s: %w", locale, err)
+	}
+	bu.AddMessages(locale, messages)
+	return nil
+}
+
+func (bu *Bundle) LoadFile(path string) error {
+	return bu.LoadFileWith(path, nil)
+}
+
+// LoadFileWith reads a locale file named after its locale. .json files are
+// decoded with encoding/json, any other extension with unmarshal.
+func (bu *Bundle) LoadFileWith(path string, unmarshal func([]byte, interface{}) error) error {
+	ext := filepath.Ext(path)
+	if ext == ".json" {
+		unmarshal = json.Unmarshal
+	} else if unmarshal == nil {
+		return fmt.Errorf("locale file %s: no unmarshal function for %s", path, ext)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	locale := strings.TrimSuffix(filepath.Base(path), ext)
+	return bu.LoadData(locale, data, unmarshal)
+}
+
+func (bu *Bundle) LoadDir(dir string) error {
+	return bu.LoadDirWith(dir, nil)
+}
+
+// LoadDirWith loads every *.json file in dir and, when unmarshal is set, every
+// *.yaml and *.yml file, e.g. LoadDirWith("locales", yaml.Unmarshal).
+func (bu *Bundle) LoadDirWith(dir string, unmarshal func([]byte, interface{}) error) error {
+	patterns := []string{"*.json"}
+	if unmarshal != nil {
+		patterns = append(patterns, "*.yaml", "*.yml")
+	}
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if err := bu.LoadFileWith(path, unmarshal); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (bu *Bundle) HasLocale(locale string) bool {
+	bu.mu.RLock()
+	defer bu.mu.RUnlock()
+	_, ok := bu.messages[normalizeLocale(locale)]
+	return ok
+}
+
+func (bu *Bundle) Match(languageCode string) string {
+	if languageCode == "" {
+		return bu.DefaultLocale
+	}
+	if bu.HasLocale(languageCode) {
+		return normalizeLocale(languageCode)
+	}
+	if base := baseLanguage(languageCode); bu.HasLocale(base) {
+		return base
+	}
+	return bu.DefaultLocale
+}
+
+func (bu *Bundle) Translate(locale string, key string, args map[string]interface{}) string {
+	bu.mu.RLock()
+	message, found, ok := bu.lookup(locale, key)
+	if !ok {
+		message, found, ok = bu.lookup(bu.DefaultLocale, key)
+	}
+	bu.mu.RUnlock()
+	if !ok {
+		return key
+	}
+
+	text, isText := message.(string)
+	if !isText {
+		forms := message.(map[string]string)
+		text, isText = forms[pluralForm(found, pluralCount(args))]
+		if !isText {
+			text = forms["other"]
+		}
+	}
+
+	for name, value := range args {
+		text = strings.ReplaceAll(text, "{"+name+"}", fmt.Sprint(value))
+	}
+	return text
+}
+
+func (bu *Bundle) lookup(locale string, key string) (interface{}, string, bool) {
+	locale = normalizeLocale(locale)
+	for _, candidate := range []string{locale, baseLanguage(locale)} {
+		if message, ok := bu.messages[candidate][key]; ok {
+			return message, candidate, true
+		}
+	}
+	return nil, "", false
+}
+
+var pluralForms = map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}
+
+func flattenMessages(dst map[string]interface{}, prefix string, src map[string]interface{}) {
+	for key, value := range src {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if text, ok := value.(string); ok {
+			dst[key] = text
+		} else if nested, ok := stringMap(value); ok {
+			if forms, ok := pluralMessage(nested); ok {
+				dst[key] = forms
+			} else {
+				flattenMessages(dst, key, nested)
+			}
+		} else {
+			dst[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+// stringMap accepts both JSON objects and the map[interface{}]interface{}
+// that yaml.v2 produces for nested mappings.
+func stringMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = item
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+func pluralMessage(value map[string]interface{}) (map[string]string, bool) {
+	forms := make(map[string]string, len(value))
+	for form, text := range value {
+		s, ok := text.(string)
+		if !ok || !pluralForms[form] {
+			return nil, false
+		}
+		forms[form] = s
+	}
+	return forms, len(forms) > 0
+}
+
+func pluralCount(args map[string]interface{}) int64 {
+	switch n := args["count"].(type) {
+	case int:
+		return int64(n)
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case uint:
+		return int64(n)
+	case uint8:
+		return int64(n)
+	case uint16:
+		return int64(n)
+	case uint32:
+		return int64(n)
+	case uint64:
+		return int64(n)
+	case float32:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case string:
+		if count, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+			return int64(count)
+		}
+	}
+	return 0
+}
+
+func pluralForm(locale string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch baseLanguage(locale) {
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		}
+		return "many"
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		}
+		return "many"
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+		return "other"
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	case "ja", "ko", "zh", "vi", "th", "id", "ms":
+		return "other"
+	}
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+func baseLanguage(locale string) string {
+	locale = normalizeLocale(locale)
+	if i := strings.Index(locale, "-"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+const localeStateKey = "locale"
+
+func (b *Bot) UseBundle(bundle *Bundle) {
+	b.bundle = bundle
+}
+
+func (b *Bot) SetLocale(userID int64, locale string) {
+	b.SetState(userID, localeStateKey, locale)
+}
+
+func (b *Bot) Locale(update Update) string {
+	if b.bundle == nil {
+		return ""
+	}
+	if locale, ok := b.GetState(updateUserID(update), localeStateKey).(string); ok && locale != "" {
+		return b.bundle.Match(locale)
+	}
+	return b.bundle.Match(updateLanguageCode(update))
+}
+
+func (b *Bot) T(update Update, key string, args map[string]interface{}) string {
+	if b.bundle == nil {
+		return key
+	}
+	return b.bundle.Translate(b.Locale(update), key, args)
+}
+
+func (b *Bot) Translator(update Update) func(key string, args map[string]interface{}) string {
+	locale := b.Locale(update)
+	return func(key string, args map[string]interface{}) string {
+		if b.bundle == nil {
+			return key
+		}
+		return b.bundle.Translate(locale, key, args)
+	}
+}
+
+func updateLanguageCode(update Update) string {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return update.Message.From.LanguageCode
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		return update.CallbackQuery.From.LanguageCode
+	case update.InlineQuery != nil && update.InlineQuery.From != nil:
+		return update.InlineQuery.From.LanguageCode
+	case update.PreCheckoutQuery != nil && update.PreCheckoutQuery.From != nil:
+		return update.PreCheckoutQuery.From.LanguageCode
+	}
+	return ""
+}
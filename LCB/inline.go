@@ -0,0 +1,340 @@
+package LCB
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ChosenInlineResult struct {
+	ResultID        string    `json:"result_id"`
+	From            *User     `json:"from"`
+	Location        *Location `json:"location"`
+	InlineMessageID string    `json:"inline_message_id"`
+	Query           string    `json:"query"`
+}
+
+type Location struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+type FilterInlineQuery struct {
+	Query  string
+	Prefix string
+}
+
+type FilterChosenInlineResult struct {
+	ResultID string
+}
+
+func (f FilterInlineQuery) Match(update Update) bool {
+	if update.InlineQuery == nil {
+		return false
+	}
+	if f.Query != "" && update.InlineQuery.Query != f.Query {
+		return false
+	}
+	return strings.HasPrefix(update.InlineQuery.Query, f.Prefix)
+}
+
+func (f FilterChosenInlineResult) Match(update Update) bool {
+	if update.ChosenInlineResult == nil {
+		return false
+	}
+	if f.ResultID == "" {
+		return true
+	}
+	return update.ChosenInlineResult.ResultID == f.ResultID
+}
+
+type InlineQueryResult interface {
+	resultType() string
+}
+
+type InputMessageContent interface {
+	inputMessageContent()
+}
+
+type InputTextMessageContent struct {
+	MessageText string          `json:"message_text"`
+	ParseMode   string          `json:"parse_mode,omitempty"`
+	Entities    []MessageEntity `json:"entities,omitempty"`
+}
+
+type InputLocationMessageContent struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	LivePeriod int     `json:"live_period,omitempty"`
+}
+
+type InputVenueMessageContent struct {
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Title        string  `json:"title"`
+	Address      string  `json:"address"`
+	FoursquareID string  `json:"foursquare_id,omitempty"`
+}
+
+type InputContactMessageContent struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	VCard       string `json:"vcard,omitempty"`
+}
+
+func (InputTextMessageContent) inputMessageContent()     {}
+func (InputLocationMessageContent) inputMessageContent() {}
+func (InputVenueMessageContent) inputMessageContent()    {}
+func (InputContactMessageContent) inputMessageContent()  {}
+
+type InlineQueryResultArticle struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	InputMessageContent InputMessageContent   `json:"input_message_content"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+}
+
+type InlineQueryResultPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoURL            string                `json:"photo_url"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	PhotoWidth          int                   `json:"photo_width,omitempty"`
+	PhotoHeight         int                   `json:"photo_height,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultGif struct {
+	ID                  string                `json:"id"`
+	GifURL              string                `json:"gif_url"`
+	GifWidth            int                   `json:"gif_width,omitempty"`
+	GifHeight           int                   `json:"gif_height,omitempty"`
+	GifDuration         int                   `json:"gif_duration,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultVideo struct {
+	ID                  string                `json:"id"`
+	VideoURL            string                `json:"video_url"`
+	MimeType            string                `json:"mime_type"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	VideoWidth          int                   `json:"video_width,omitempty"`
+	VideoHeight         int                   `json:"video_height,omitempty"`
+	VideoDuration       int                   `json:"video_duration,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	DocumentURL         string                `json:"document_url"`
+	MimeType            string                `json:"mime_type"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+}
+
+type InlineQueryResultLocation struct {
+	ID                  string                `json:"id"`
+	Latitude            float64               `json:"latitude"`
+	Longitude           float64               `json:"longitude"`
+	Title               string                `json:"title"`
+	LivePeriod          int                   `json:"live_period,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+}
+
+type InlineQueryResultVenue struct {
+	ID                  string                `json:"id"`
+	Latitude            float64               `json:"latitude"`
+	Longitude           float64               `json:"longitude"`
+	Title               string                `json:"title"`
+	Address             string                `json:"address"`
+	FoursquareID        string                `json:"foursquare_id,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+}
+
+type InlineQueryResultContact struct {
+	ID                  string                `json:"id"`
+	PhoneNumber         string                `json:"phone_number"`
+	FirstName           string                `json:"first_name"`
+	LastName            string                `json:"last_name,omitempty"`
+	VCard               string                `json:"vcard,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+}
+
+type InlineQueryResultCachedPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoFileID         string                `json:"photo_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedGif struct {
+	ID                  string                `json:"id"`
+	GifFileID           string                `json:"gif_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedVideo struct {
+	ID                  string                `json:"id"`
+	VideoFileID         string                `json:"video_file_id"`
+	Title               string                `json:"title"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	DocumentFileID      string                `json:"document_file_id"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedSticker struct {
+	ID                  string                `json:"id"`
+	StickerFileID       string                `json:"sticker_file_id"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (InlineQueryResultArticle) resultType() string        { return "article" }
+func (InlineQueryResultPhoto) resultType() string          { return "photo" }
+func (InlineQueryResultGif) resultType() string            { return "gif" }
+func (InlineQueryResultVideo) resultType() string          { return "video" }
+func (InlineQueryResultDocument) resultType() string       { return "document" }
+func (InlineQueryResultLocation) resultType() string       { return "location" }
+func (InlineQueryResultVenue) resultType() string          { return "venue" }
+func (InlineQueryResultContact) resultType() string        { return "contact" }
+func (InlineQueryResultCachedPhoto) resultType() string    { return "photo" }
+func (InlineQueryResultCachedGif) resultType() string      { return "gif" }
+func (InlineQueryResultCachedVideo) resultType() string    { return "video" }
+func (InlineQueryResultCachedDocument) resultType() string { return "document" }
+func (InlineQueryResultCachedSticker) resultType() string  { return "sticker" }
+
+type InlineQueryResultsButton struct {
+	Text           string      `json:"text"`
+	WebApp         *WebAppInfo `json:"web_app,omitempty"`
+	StartParameter string      `json:"start_parameter,omitempty"`
+}
+
+type InlineQueryAnswer struct {
+	// CacheTime 0 keeps Telegram's default of 300 seconds, a negative value disables caching.
+	CacheTime  int
+	IsPersonal bool
+	NextOffset string
+	Button     *InlineQueryResultsButton
+}
+
+func (b *Bot) AnswerInlineQuery(inlineQueryID string, results []InlineQueryResult, answer *InlineQueryAnswer) error {
+	encoded := make([]json.RawMessage, 0, len(results))
+	for i, result := range results {
+		if result == nil {
+			return fmt.Errorf("inline query result %d is nil", i)
+		}
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if len(raw) < 2 || raw[0] != '{' {
+			return fmt.Errorf("inline query result %d is not an object: %s", i, raw)
+		}
+		typed := `{"type":` + strconv.Quote(result.resultType())
+		if len(raw) > 2 {
+			typed += ","
+		}
+		encoded = append(encoded, json.RawMessage(typed+string(raw[1:])))
+	}
+
+	message := map[string]interface{}{
+		"inline_query_id": inlineQueryID,
+		"results":         encoded,
+	}
+	if answer != nil {
+		if answer.CacheTime > 0 {
+			message["cache_time"] = answer.CacheTime
+		} else if answer.CacheTime < 0 {
+			message["cache_time"] = 0
+		}
+		if answer.IsPersonal {
+			message["is_personal"] = true
+		}
+		if answer.NextOffset != "" {
+			message["next_offset"] = answer.NextOffset
+		}
+		if answer.Button != nil {
+			message["button"] = answer.Button
+		}
+	}
+
+	return b.callMethod("answerInlineQuery", message, nil)
+}
+
+// maxInlineResults is the most results answerInlineQuery accepts at once.
+const maxInlineResults = 50
+
+// InlinePage returns the slice bounds for the page at offset and the
+// next_offset to send with it. pageSize is clamped to 1..50.
+func InlinePage(offset string, pageSize int, total int) (int, int, string) {
+	if pageSize <= 0 || pageSize > maxInlineResults {
+		pageSize = maxInlineResults
+	}
+	start, err := strconv.Atoi(offset)
+	if err != nil || start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end >= total {
+		return start, total, ""
+	}
+	return start, end, strconv.Itoa(end)
+}
@@ -0,0 +1,142 @@
+package LCB
+
+type ChatInviteLink struct {
+	InviteLink              string `json:"invite_link"`
+	Creator                 *User  `json:"creator"`
+	CreatesJoinRequest      bool   `json:"creates_join_request"`
+	IsPrimary               bool   `json:"is_primary"`
+	IsRevoked               bool   `json:"is_revoked"`
+	Name                    string `json:"name"`
+	ExpireDate              int64  `json:"expire_date"`
+	MemberLimit             int    `json:"member_limit"`
+	PendingJoinRequestCount int    `json:"pending_join_request_count"`
+}
+
+// ChatInviteLinkOptions leaves zero fields out of the request. When editing a
+// link, the Clear flags remove the expiry and member limit or turn join
+// requests off again.
+type ChatInviteLinkOptions struct {
+	Name                    string
+	ExpireDate              int64
+	MemberLimit             int
+	CreatesJoinRequest      bool
+	ClearExpireDate         bool
+	ClearMemberLimit        bool
+	ClearCreatesJoinRequest bool
+}
+
+type ChatJoinRequest struct {
+	Chat       *Chat           `json:"chat"`
+	From       *User           `json:"from"`
+	UserChatID int64           `json:"user_chat_id"`
+	Date       int64           `json:"date"`
+	Bio        string          `json:"bio"`
+	InviteLink *ChatInviteLink `json:"invite_link"`
+}
+
+type FilterChatJoinRequest struct {
+	ChatID int64
+}
+
+func (f FilterChatJoinRequest) Match(update Update) bool {
+	if update.ChatJoinRequest == nil {
+		return false
+	}
+	if f.ChatID == 0 {
+		return true
+	}
+	return update.ChatJoinRequest.Chat != nil && update.ChatJoinRequest.Chat.ID == f.ChatID
+}
+
+func (o ChatInviteLinkOptions) apply(message map[string]interface{}) {
+	if o.Name != "" {
+		message["name"] = o.Name
+	}
+	if o.ExpireDate != 0 {
+		message["expire_date"] = o.ExpireDate
+	} else if o.ClearExpireDate {
+		message["expire_date"] = 0
+	}
+	if o.MemberLimit != 0 {
+		message["member_limit"] = o.MemberLimit
+	} else if o.ClearMemberLimit {
+		message["member_limit"] = 0
+	}
+	if o.CreatesJoinRequest {
+		message["creates_join_request"] = true
+	} else if o.ClearCreatesJoinRequest {
+		message["creates_join_request"] = false
+	}
+}
+
+func (b *Bot) CreateChatInviteLink(chatID int64, opts ChatInviteLinkOptions) (*ChatInviteLink, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	opts.apply(message)
+
+	var result ChatInviteLink
+	if err := b.callMethod("createChatInviteLink", message, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (b *Bot) EditChatInviteLink(chatID int64, inviteLink string, opts ChatInviteLinkOptions) (*ChatInviteLink, error) {
+	message := map[string]interface{}{
+		"chat_id":     chatID,
+		"invite_link": inviteLink,
+	}
+
+	opts.apply(message)
+
+	var result ChatInviteLink
+	if err := b.callMethod("editChatInviteLink", message, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (b *Bot) RevokeChatInviteLink(chatID int64, inviteLink string) (*ChatInviteLink, error) {
+	message := map[string]interface{}{
+		"chat_id":     chatID,
+		"invite_link": inviteLink,
+	}
+
+	var result ChatInviteLink
+	if err := b.callMethod("revokeChatInviteLink", message, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (b *Bot) ExportChatInviteLink(chatID int64) (string, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	var result string
+	if err := b.callMethod("exportChatInviteLink", message, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func (b *Bot) ApproveChatJoinRequest(chatID int64, userID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	return b.callMethod("approveChatJoinRequest", message, nil)
+}
+
+func (b *Bot) DeclineChatJoinRequest(chatID int64, userID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	return b.callMethod("declineChatJoinRequest", message, nil)
+}
@@ -0,0 +1,245 @@
+package LCB
+
+import (
+	"fmt"
+	"strings"
+)
+
+type KeyboardButtonPollType struct {
+	Type string `json:"type,omitempty"`
+}
+
+type KeyboardButtonRequestUsers struct {
+	RequestID       int   `json:"request_id"`
+	UserIsBot       *bool `json:"user_is_bot,omitempty"`
+	UserIsPremium   *bool `json:"user_is_premium,omitempty"`
+	MaxQuantity     int   `json:"max_quantity,omitempty"`
+	RequestName     bool  `json:"request_name,omitempty"`
+	RequestUsername bool  `json:"request_username,omitempty"`
+	RequestPhoto    bool  `json:"request_photo,omitempty"`
+}
+
+type KeyboardButtonRequestChat struct {
+	RequestID       int   `json:"request_id"`
+	ChatIsChannel   bool  `json:"chat_is_channel"`
+	ChatIsForum     *bool `json:"chat_is_forum,omitempty"`
+	ChatHasUsername *bool `json:"chat_has_username,omitempty"`
+	ChatIsCreated   *bool `json:"chat_is_created,omitempty"`
+	BotIsMember     bool  `json:"bot_is_member,omitempty"`
+	RequestTitle    bool  `json:"request_title,omitempty"`
+	RequestUsername bool  `json:"request_username,omitempty"`
+	RequestPhoto    bool  `json:"request_photo,omitempty"`
+}
+
+func NewInline() *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{}}
+}
+
+func (k *InlineKeyboardMarkup) Row(buttons ...InlineKeyboardButton) *InlineKeyboardMarkup {
+	if len(buttons) > 0 {
+		k.InlineKeyboard = append(k.InlineKeyboard, buttons)
+	}
+	return k
+}
+
+func (k *InlineKeyboardMarkup) Grid(buttons []InlineKeyboardButton, columns int) *InlineKeyboardMarkup {
+	if columns <= 0 {
+		columns = 1
+	}
+	for start := 0; start < len(buttons); start += columns {
+		end := start + columns
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		row := make([]InlineKeyboardButton, end-start)
+		copy(row, buttons[start:end])
+		k.InlineKeyboard = append(k.InlineKeyboard, row)
+	}
+	return k
+}
+
+func (k *InlineKeyboardMarkup) Keyboards() *Keyboards {
+	return &Keyboards{Inline: k}
+}
+
+func Btn(text string, callbackData string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: callbackData}
+}
+
+func URLBtn(text string, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+func WebAppBtn(text string, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+func SwitchInlineBtn(text string, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+func SwitchInlineCurrentChatBtn(text string, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query}
+}
+
+func PayBtn(text string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, Pay: true}
+}
+
+func NewReply() *ReplyKeyboardMarkup {
+	return &ReplyKeyboardMarkup{ReplyKeyboard: [][]ReplyKeyboardButton{}}
+}
+
+func (k *ReplyKeyboardMarkup) Row(buttons ...ReplyKeyboardButton) *ReplyKeyboardMarkup {
+	if len(buttons) > 0 {
+		k.ReplyKeyboard = append(k.ReplyKeyboard, buttons)
+	}
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) Grid(buttons []ReplyKeyboardButton, columns int) *ReplyKeyboardMarkup {
+	if columns <= 0 {
+		columns = 1
+	}
+	for start := 0; start < len(buttons); start += columns {
+		end := start + columns
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		row := make([]ReplyKeyboardButton, end-start)
+		copy(row, buttons[start:end])
+		k.ReplyKeyboard = append(k.ReplyKeyboard, row)
+	}
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) Resize() *ReplyKeyboardMarkup {
+	k.ResizeKeyboard = true
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) OneTime() *ReplyKeyboardMarkup {
+	k.OneTimeKeyboard = true
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) Persistent() *ReplyKeyboardMarkup {
+	k.IsPersistent = true
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) Placeholder(text string) *ReplyKeyboardMarkup {
+	k.InputFieldPlaceholder = text
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) SelectiveOnly() *ReplyKeyboardMarkup {
+	k.Selective = true
+	return k
+}
+
+func (k *ReplyKeyboardMarkup) Keyboards() *Keyboards {
+	return &Keyboards{Reply: k}
+}
+
+func ReplyBtn(text string) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text}
+}
+
+func ContactBtn(text string) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text, RequestContact: true}
+}
+
+func LocationBtn(text string) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text, RequestLocation: true}
+}
+
+// pollType is "quiz", "regular" or "" to let the user choose.
+func PollBtn(text string, pollType string) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text, RequestPoll: &KeyboardButtonPollType{Type: pollType}}
+}
+
+func UsersBtn(text string, requestID int, maxQuantity int) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text, RequestUsers: &KeyboardButtonRequestUsers{RequestID: requestID, MaxQuantity: maxQuantity}}
+}
+
+func ChatBtn(text string, requestID int, isChannel bool) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text, RequestChat: &KeyboardButtonRequestChat{RequestID: requestID, ChatIsChannel: isChannel}}
+}
+
+func WebAppReplyBtn(text string, url string) ReplyKeyboardButton {
+	return ReplyKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+func NewForceReply(placeholder string) *ForceReply {
+	return &ForceReply{ForceReply: true, InputFieldPlaceholder: placeholder}
+}
+
+func (f *ForceReply) SelectiveOnly() *ForceReply {
+	f.Selective = true
+	return f
+}
+
+func (f *ForceReply) Keyboards() *Keyboards {
+	return &Keyboards{ForceReply: f}
+}
+
+func NewDeleteKeyboard() *DeleteKeyboard {
+	return &DeleteKeyboard{Remove_keyboard: true}
+}
+
+func (d *DeleteKeyboard) SelectiveOnly() *DeleteKeyboard {
+	d.Selective = true
+	return d
+}
+
+func (d *DeleteKeyboard) Keyboards() *Keyboards {
+	return &Keyboards{Delete: d}
+}
+
+func (k *Keyboards) ReplyMarkup() (interface{}, error) {
+	if k == nil {
+		return nil, nil
+	}
+
+	var markup interface{}
+	set := []string{}
+	if k.Inline != nil {
+		markup = k.Inline
+		set = append(set, "Inline")
+	}
+	if k.Reply != nil {
+		markup = k.Reply
+		set = append(set, "Reply")
+	}
+	if k.Delete != nil {
+		markup = DeleteKeyboard{Remove_keyboard: true, Selective: k.Delete.Selective}
+		set = append(set, "Delete")
+	}
+	if k.ForceReply != nil {
+		forceReply := *k.ForceReply
+		forceReply.ForceReply = true
+		markup = forceReply
+		set = append(set, "ForceReply")
+	}
+
+	if len(set) > 1 {
+		return nil, fmt.Errorf("conflicting reply markups: %s", strings.Join(set, ", "))
+	}
+	return markup, nil
+}
+
+// InlineMarkup is ReplyMarkup for methods that only accept an inline keyboard,
+// such as the editMessage* family.
+func (k *Keyboards) InlineMarkup() (interface{}, error) {
+	if k == nil {
+		return nil, nil
+	}
+	if k.Reply != nil || k.Delete != nil || k.ForceReply != nil {
+		return nil, fmt.Errorf("only an inline keyboard can be attached to this message")
+	}
+	if k.Inline == nil {
+		return nil, nil
+	}
+	return k.Inline, nil
+}
@@ -0,0 +1,223 @@
+package LCB
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+type Menu struct {
+	ID        string
+	Text      string
+	ParseMode string
+	Columns   int
+	Buttons   []MenuButton
+	Dynamic   func(userID int64) []MenuButton
+	Visible   func(userID int64) bool
+}
+
+// MenuButton.ID identifies an action button in callback data and defaults to
+// Text; keep it unique within a menu.
+type MenuButton struct {
+	ID      string
+	Text    string
+	Submenu string
+	URL     string
+	Action  func(update Update)
+	Visible func(userID int64) bool
+}
+
+type MenuTree struct {
+	Name     string
+	Root     string
+	BackText string
+	HomeText string
+	menus    map[string]*Menu
+	bot      *Bot
+}
+
+func (b *Bot) NewMenuTree(name string, root *Menu, menus ...*Menu) *MenuTree {
+	m := &MenuTree{
+		Name:     name,
+		Root:     root.ID,
+		BackText: "« Back",
+		HomeText: "⌂ Home",
+		menus:    make(map[string]*Menu),
+		bot:      b,
+	}
+	m.Add(root)
+	for _, menu := range menus {
+		m.Add(menu)
+	}
+	b.AddHandler(FilterCallbackPrefix{Prefix: m.prefix()}, m.handle)
+	return m
+}
+
+func (m *MenuTree) Add(menu *Menu) *MenuTree {
+	m.menus[menu.ID] = menu
+	return m
+}
+
+func (m *MenuTree) prefix() string {
+	return "mn:" + m.Name + ":"
+}
+
+func (m *MenuTree) stateKey() string {
+	return "menu:" + m.Name
+}
+
+func (m *MenuTree) history(userID int64) []string {
+	history, _ := m.bot.GetState(userID, m.stateKey()).([]string)
+	if len(history) == 0 {
+		return []string{m.Root}
+	}
+	return history
+}
+
+func (m *MenuTree) setHistory(userID int64, history []string) {
+	m.bot.SetState(userID, m.stateKey(), history)
+}
+
+func (m *MenuTree) visible(menu *Menu, userID int64) bool {
+	return menu != nil && (menu.Visible == nil || menu.Visible(userID))
+}
+
+func (m *MenuTree) buttons(menu *Menu, userID int64) []MenuButton {
+	all := append([]MenuButton{}, menu.Buttons...)
+	if menu.Dynamic != nil {
+		all = append(all, menu.Dynamic(userID)...)
+	}
+
+	buttons := []MenuButton{}
+	for _, button := range all {
+		if button.Visible != nil && !button.Visible(userID) {
+			continue
+		}
+		if button.Submenu != "" && !m.visible(m.menus[button.Submenu], userID) {
+			continue
+		}
+		buttons = append(buttons, button)
+	}
+	return buttons
+}
+
+func (b MenuButton) actionID() string {
+	if b.ID != "" {
+		return b.ID
+	}
+	return b.Text
+}
+
+func (m *MenuTree) callbackBtn(text string, payload string) (InlineKeyboardButton, error) {
+	if m.bot.callbackCodec == nil && len(payload) > maxCallbackDataLen {
+		return InlineKeyboardButton{}, fmt.Errorf("menu %s: callback data %q exceeds %d bytes, shorten the IDs or enable UseCallbackCodec", m.Name, payload, maxCallbackDataLen)
+	}
+	data, err := m.bot.TryCallbackData(payload)
+	if err != nil {
+		return InlineKeyboardButton{}, fmt.Errorf("menu %s: %w", m.Name, err)
+	}
+	return Btn(text, data), nil
+}
+
+func (m *MenuTree) Keyboard(menu *Menu, userID int64, depth int) (*InlineKeyboardMarkup, error) {
+	buttons := []InlineKeyboardButton{}
+	for _, button := range m.buttons(menu, userID) {
+		if button.URL != "" {
+			buttons = append(buttons, URLBtn(button.Text, button.URL))
+			continue
+		}
+		payload := m.prefix() + "a:" + menu.ID + ":" + button.actionID()
+		if button.Submenu != "" {
+			payload = m.prefix() + "o:" + button.Submenu
+		}
+		btn, err := m.callbackBtn(button.Text, payload)
+		if err != nil {
+			return nil, err
+		}
+		buttons = append(buttons, btn)
+	}
+
+	columns := menu.Columns
+	if columns <= 0 {
+		columns = 1
+	}
+	keyboard := NewInline().Grid(buttons, columns)
+
+	nav := []InlineKeyboardButton{}
+	if depth > 1 {
+		nav = append(nav, m.bot.CallbackBtn(m.BackText, m.prefix()+"b"))
+	}
+	if depth > 2 {
+		nav = append(nav, m.bot.CallbackBtn(m.HomeText, m.prefix()+"h"))
+	}
+	return keyboard.Row(nav...), nil
+}
+
+func (m *MenuTree) Show(chatID int64, userID int64) int {
+	root := m.menus[m.Root]
+	keyboard, err := m.Keyboard(root, userID, 1)
+	if err != nil {
+		log.Println("Error building menu:", err)
+		return 0
+	}
+	m.setHistory(userID, []string{m.Root})
+	return m.bot.SendMessage(chatID, root.Text, root.ParseMode, keyboard.Keyboards())
+}
+
+func (m *MenuTree) handle(update Update) {
+	query := update.CallbackQuery
+	if query.Message == nil || query.Message.Chat == nil || query.From == nil {
+		return
+	}
+	userID := query.From.ID
+	history := append([]string{}, m.history(userID)...)
+
+	parts := strings.SplitN(strings.TrimPrefix(query.Data, m.prefix()), ":", 3)
+	switch parts[0] {
+	case "o":
+		if len(parts) < 2 || !m.visible(m.menus[parts[1]], userID) {
+			return
+		}
+		history = append(history, parts[1])
+	case "b":
+		if len(history) > 1 {
+			history = history[:len(history)-1]
+		}
+	case "h":
+		history = []string{m.Root}
+	case "a":
+		if len(parts) < 3 {
+			return
+		}
+		menu := m.menus[parts[1]]
+		if !m.visible(menu, userID) {
+			return
+		}
+		for _, button := range m.buttons(menu, userID) {
+			if button.URL == "" && button.Submenu == "" && button.actionID() == parts[2] {
+				if button.Action != nil {
+					button.Action(update)
+				}
+				return
+			}
+		}
+		return
+	default:
+		return
+	}
+
+	menu := m.menus[history[len(history)-1]]
+	if menu == nil {
+		return
+	}
+	keyboard, err := m.Keyboard(menu, userID, len(history))
+	if err != nil {
+		log.Println("Error building menu:", err)
+		return
+	}
+	m.setHistory(userID, history)
+	if err := m.bot.AnswerCallbackQuery(query.ID, "", false, "", 0); err != nil {
+		log.Println("Error answering callback:", err)
+	}
+	m.bot.EditMessage(query.Message.Chat.ID, query.Message.Message_id, menu.Text, menu.ParseMode, keyboard.Keyboards())
+}
@@ -0,0 +1,140 @@
+package LCB
+
+type MessageID struct {
+	MessageID int `json:"message_id"`
+}
+
+func messageIDList(ids []MessageID) []int {
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.MessageID)
+	}
+	return result
+}
+
+func (b *Bot) DeleteMessages(chatID int64, messageIDs []int64) error {
+	message := map[string]interface{}{
+		"chat_id":     chatID,
+		"message_ids": messageIDs,
+	}
+
+	return b.callMethod("deleteMessages", message, nil)
+}
+
+func (b *Bot) ForwardMessage(chatID int64, fromChatID int64, messageID int64, opts ...SendOptions) (int, error) {
+	message := map[string]interface{}{
+		"chat_id":      chatID,
+		"from_chat_id": fromChatID,
+		"message_id":   messageID,
+	}
+
+	sendOptions(opts).apply(message, forwardMessageOptions)
+
+	var result MessageID
+	if err := b.callMethod("forwardMessage", message, &result); err != nil {
+		return 0, err
+	}
+	return result.MessageID, nil
+}
+
+func (b *Bot) ForwardMessages(chatID int64, fromChatID int64, messageIDs []int64, opts ...SendOptions) ([]int, error) {
+	message := map[string]interface{}{
+		"chat_id":      chatID,
+		"from_chat_id": fromChatID,
+		"message_ids":  messageIDs,
+	}
+
+	sendOptions(opts).apply(message, forwardMessageOptions)
+
+	var result []MessageID
+	if err := b.callMethod("forwardMessages", message, &result); err != nil {
+		return nil, err
+	}
+	return messageIDList(result), nil
+}
+
+func (b *Bot) CopyMessage(chatID int64, fromChatID int64, messageID int64, caption string, parseMode string, keyboards *Keyboards, opts ...SendOptions) (int, error) {
+	message := map[string]interface{}{
+		"chat_id":      chatID,
+		"from_chat_id": fromChatID,
+		"message_id":   messageID,
+	}
+
+	sendOptions(opts).apply(message, copyMessageOptions)
+
+	if caption != "" {
+		message["caption"] = caption
+	}
+
+	if parseMode != "" {
+		message["parse_mode"] = parseMode
+	}
+
+	markup, err := keyboards.ReplyMarkup()
+	if err != nil {
+		return 0, err
+	}
+	if markup != nil {
+		message["reply_markup"] = markup
+	}
+
+	var result MessageID
+	if err := b.callMethod("copyMessage", message, &result); err != nil {
+		return 0, err
+	}
+	return result.MessageID, nil
+}
+
+func (b *Bot) CopyMessages(chatID int64, fromChatID int64, messageIDs []int64, removeCaption bool, opts ...SendOptions) ([]int, error) {
+	message := map[string]interface{}{
+		"chat_id":      chatID,
+		"from_chat_id": fromChatID,
+		"message_ids":  messageIDs,
+	}
+
+	sendOptions(opts).apply(message, copyMessagesOptions)
+
+	if removeCaption {
+		message["remove_caption"] = true
+	}
+
+	var result []MessageID
+	if err := b.callMethod("copyMessages", message, &result); err != nil {
+		return nil, err
+	}
+	return messageIDList(result), nil
+}
+
+func (b *Bot) PinChatMessage(chatID int64, messageID int64, disableNotification bool) error {
+	message := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	}
+
+	if disableNotification {
+		message["disable_notification"] = true
+	}
+
+	return b.callMethod("pinChatMessage", message, nil)
+}
+
+// A zero messageID unpins the most recent pinned message.
+func (b *Bot) UnpinChatMessage(chatID int64, messageID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	if messageID != 0 {
+		message["message_id"] = messageID
+	}
+
+	return b.callMethod("unpinChatMessage", message, nil)
+}
+
+func (b *Bot) UnpinAllChatMessages(chatID int64) error {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	return b.callMethod("unpinAllChatMessages", message, nil)
+}
@@ -0,0 +1,216 @@
+package LCB
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAckTimeout      = 30 * time.Second
+	defaultDedupWindow     = 1000
+	defaultHandlerAttempts = 1
+	handlerRetryDelay      = time.Second
+	pollIdleDelay          = 250 * time.Millisecond
+	maxPollBackoff         = 30 * time.Second
+	pollTimeout            = 30
+)
+
+type OffsetStore interface {
+	LoadOffset() (int64, error)
+	SaveOffset(offset int64) error
+}
+
+type FileOffsetStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileOffsetStore(path string) *FileOffsetStore {
+	return &FileOffsetStore{Path: path}
+}
+
+func (s *FileOffsetStore) LoadOffset() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(text, 10, 64)
+}
+
+func (s *FileOffsetStore) SaveOffset(offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dir := filepath.Dir(s.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+func defaultOffsetPath(token string) string {
+	botID := token
+	if i := strings.Index(token, ":"); i >= 0 {
+		botID = token[:i]
+	}
+	return "lcb-offset-" + botID
+}
+
+func (b *Bot) UseOffsetStore(store OffsetStore) {
+	b.offsetStore = store
+}
+
+// updateTracker decides which update_ids to dispatch and which offset is safe
+// to commit. Polling starts from the committed offset, so Telegram keeps every
+// update that is still being handled and returns it again after a crash; the
+// pending and seen sets skip those redeliveries while the process is alive.
+// The committed offset never moves past an update that is still in flight,
+// unless it has been running for longer than the ack timeout.
+type updateTracker struct {
+	mu        sync.Mutex
+	committed int64
+	maxSeen   int64
+	pending   map[int64]time.Time
+	seen      map[int64]bool
+	recent    []int64
+	window    int
+}
+
+func newUpdateTracker(offset int64, window int) *updateTracker {
+	if window <= 0 {
+		window = defaultDedupWindow
+	}
+	return &updateTracker{
+		committed: offset,
+		maxSeen:   offset - 1,
+		pending:   make(map[int64]time.Time),
+		seen:      make(map[int64]bool),
+		window:    window,
+	}
+}
+
+func (t *updateTracker) dispatch(updateID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, pending := t.pending[updateID]; pending || updateID < t.committed || t.seen[updateID] {
+		return false
+	}
+	t.remember(updateID)
+	t.pending[updateID] = time.Now()
+	if updateID > t.maxSeen {
+		t.maxSeen = updateID
+	}
+	return true
+}
+
+func (t *updateTracker) remember(updateID int64) {
+	t.seen[updateID] = true
+	t.recent = append(t.recent, updateID)
+	if len(t.recent) > t.window {
+		delete(t.seen, t.recent[0])
+		t.recent = t.recent[1:]
+	}
+}
+
+// ack marks an update as handled by every matching handler.
+func (t *updateTracker) ack(updateID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, updateID)
+}
+
+func (t *updateTracker) idle() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending) == 0
+}
+
+// offset returns the offset to poll from and persist: every update below it
+// has been handled, given up on, or outlived the ack timeout.
+func (t *updateTracker) offset(ackTimeout time.Duration) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offset := t.maxSeen + 1
+	for updateID, started := range t.pending {
+		if time.Since(started) > ackTimeout {
+			continue
+		}
+		if updateID < offset {
+			offset = updateID
+		}
+	}
+	if offset > t.committed {
+		t.committed = offset
+	}
+	return t.committed
+}
+
+func (b *Bot) loadOffset() int64 {
+	if b.offsetStore == nil {
+		b.offsetStore = NewFileOffsetStore(defaultOffsetPath(b.Token))
+	}
+	offset, err := b.offsetStore.LoadOffset()
+	if err != nil {
+		log.Println("Error loading update offset:", err)
+		return 0
+	}
+	return offset
+}
+
+func (b *Bot) ackUpdate(updateID int64) {
+	if b.updates == nil {
+		return
+	}
+	b.updates.ack(updateID)
+	b.commitOffset()
+}
+
+// commitOffset saves the offset as soon as the handled prefix advances, so a
+// restart does not redeliver updates that were already handled.
+func (b *Bot) commitOffset() int64 {
+	ackTimeout := b.AckTimeout
+	if ackTimeout <= 0 {
+		ackTimeout = defaultAckTimeout
+	}
+	b.offsetMu.Lock()
+	defer b.offsetMu.Unlock()
+	offset := b.updates.offset(ackTimeout)
+	// a replay must never overwrite the offset of the live bot
+	if offset != b.lastUpdateId && b.offsetStore != nil && !b.replaying {
+		if err := b.offsetStore.SaveOffset(offset); err != nil {
+			log.Println("Error saving update offset:", err)
+		}
+		b.lastUpdateId = offset
+	}
+	return offset
+}
+
+func nextPollBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return time.Second
+	}
+	backoff *= 2
+	if backoff > maxPollBackoff {
+		backoff = maxPollBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,352 @@
+package LCB
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type trackerStep struct {
+	op         string // dispatch, ack or expire
+	id         int64
+	want       bool // result of dispatch
+	wantOffset int64
+}
+
+func TestUpdateTracker(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int64
+		window int
+		steps  []trackerStep
+	}{
+		{"ack in order", 10, 0, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"dispatch", 11, true, 10},
+			{"ack", 10, false, 11},
+			{"ack", 11, false, 12},
+		}},
+		{"ack out of order", 10, 0, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"dispatch", 11, true, 10},
+			{"dispatch", 12, true, 10},
+			{"ack", 12, false, 10},
+			{"ack", 11, false, 10},
+			{"ack", 10, false, 13},
+		}},
+		{"gaps in update ids", 10, 0, []trackerStep{
+			{"dispatch", 15, true, 15},
+			{"ack", 15, false, 16},
+		}},
+		{"redelivery while pending", 10, 0, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"dispatch", 10, false, 10},
+		}},
+		{"redelivery after ack behind a pending update", 10, 0, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"dispatch", 11, true, 10},
+			{"ack", 11, false, 10},
+			{"dispatch", 11, false, 10},
+		}},
+		{"below committed offset", 10, 0, []trackerStep{
+			{"dispatch", 9, false, 10},
+		}},
+		{"pending survives dedup eviction", 10, 1, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"dispatch", 11, true, 10},
+			{"dispatch", 10, false, 10},
+		}},
+		{"ack of unknown update", 10, 0, []trackerStep{
+			{"ack", 10, false, 10},
+		}},
+		{"ack timeout releases the offset", 10, 0, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"dispatch", 11, true, 10},
+			{"ack", 11, false, 10},
+			{"expire", 10, false, 12},
+			{"dispatch", 10, false, 12},
+			{"ack", 10, false, 12},
+		}},
+		{"committed offset never moves back", 10, 0, []trackerStep{
+			{"dispatch", 10, true, 10},
+			{"expire", 10, false, 11},
+			{"dispatch", 11, true, 11},
+			{"ack", 11, false, 12},
+			{"ack", 10, false, 12},
+		}},
+	}
+
+	const ackTimeout = time.Minute
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newUpdateTracker(tt.offset, tt.window)
+			for i, step := range tt.steps {
+				var got bool
+				switch step.op {
+				case "dispatch":
+					got = tracker.dispatch(step.id)
+				case "ack":
+					tracker.ack(step.id)
+				case "expire":
+					tracker.pending[step.id] = time.Now().Add(-2 * ackTimeout)
+				default:
+					t.Fatalf("step %d: unknown op %q", i, step.op)
+				}
+				if got != step.want {
+					t.Errorf("step %d: %s(%d) = %v, want %v", i, step.op, step.id, got, step.want)
+				}
+				if offset := tracker.offset(ackTimeout); offset != step.wantOffset {
+					t.Errorf("step %d: offset = %d, want %d", i, offset, step.wantOffset)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateTrackerIdle(t *testing.T) {
+	tracker := newUpdateTracker(1, 0)
+	if !tracker.idle() {
+		t.Fatal("new tracker is not idle")
+	}
+	tracker.dispatch(1)
+	if tracker.idle() {
+		t.Fatal("tracker with a pending update is idle")
+	}
+	tracker.ack(1)
+	if !tracker.idle() {
+		t.Fatal("tracker is not idle after the last ack")
+	}
+}
+
+func TestFileOffsetStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileOffsetStore(filepath.Join(dir, "data", "offset"))
+
+	if offset, err := store.LoadOffset(); err != nil || offset != 0 {
+		t.Fatalf("LoadOffset on missing file = %d, %v; want 0, nil", offset, err)
+	}
+	for _, want := range []int64{42, 7, 1 << 40} {
+		if err := store.SaveOffset(want); err != nil {
+			t.Fatal(err)
+		}
+		if offset, err := store.LoadOffset(); err != nil || offset != want {
+			t.Fatalf("LoadOffset = %d, %v; want %d, nil", offset, err, want)
+		}
+	}
+
+	if err := os.WriteFile(store.Path, []byte(" \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if offset, err := store.LoadOffset(); err != nil || offset != 0 {
+		t.Fatalf("LoadOffset on empty file = %d, %v; want 0, nil", offset, err)
+	}
+	if err := os.WriteFile(store.Path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadOffset(); err == nil {
+		t.Fatal("LoadOffset on a corrupt file succeeded")
+	}
+}
+
+type memoryOffsetStore struct {
+	mu    sync.Mutex
+	saved []int64
+}
+
+func (s *memoryOffsetStore) LoadOffset() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.saved) == 0 {
+		return 0, nil
+	}
+	return s.saved[len(s.saved)-1], nil
+}
+
+func (s *memoryOffsetStore) SaveOffset(offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = append(s.saved, offset)
+	return nil
+}
+
+func TestAckUpdateSavesOffset(t *testing.T) {
+	store := &memoryOffsetStore{}
+	b := NewBot("1:test")
+	b.UseOffsetStore(store)
+	b.updates = newUpdateTracker(5, 0)
+	b.lastUpdateId = 5
+	b.updates.dispatch(5)
+	b.updates.dispatch(6)
+
+	b.ackUpdate(6)
+	if len(store.saved) != 0 {
+		t.Fatalf("saved %v while update 5 is pending", store.saved)
+	}
+	b.ackUpdate(5)
+	if len(store.saved) != 1 || store.saved[0] != 7 {
+		t.Fatalf("saved offsets = %v, want [7]", store.saved)
+	}
+}
+
+func TestRunHandlersRetriesOnlyFailedHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		attempts      int
+		wantFlakyRuns int32
+	}{
+		{"retries are off by default", 0, 1},
+		{"failed handler is retried", 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBot("1:test")
+			b.HandlerAttempts = tt.attempts
+			b.updates = newUpdateTracker(1, 0)
+			var okRuns, flakyRuns int32
+			b.AddHandler(matchAll{}, func(update Update) {
+				atomic.AddInt32(&okRuns, 1)
+			})
+			b.AddHandler(matchAll{}, func(update Update) {
+				if atomic.AddInt32(&flakyRuns, 1) == 1 {
+					panic("first attempt fails")
+				}
+			})
+
+			b.updates.dispatch(1)
+			b.runHandlers(Update{Update_id: 1})
+			deadline := time.Now().Add(5 * time.Second)
+			for !b.updates.idle() {
+				if time.Now().After(deadline) {
+					t.Fatal("update was never acknowledged")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if got := atomic.LoadInt32(&okRuns); got != 1 {
+				t.Errorf("successful handler ran %d times, want 1", got)
+			}
+			if got := atomic.LoadInt32(&flakyRuns); got != tt.wantFlakyRuns {
+				t.Errorf("failing handler ran %d times, want %d", got, tt.wantFlakyRuns)
+			}
+		})
+	}
+}
+
+// fakeTelegram keeps updates until getUpdates is called with a higher offset,
+// like the Bot API does.
+type fakeTelegram struct {
+	mu      sync.Mutex
+	updates []int64
+}
+
+// fakeTelegramClient is one process talking to fakeTelegram; crash cuts it off.
+type fakeTelegramClient struct {
+	telegram *fakeTelegram
+	crashed  int32
+}
+
+func (c *fakeTelegramClient) crash() {
+	atomic.StoreInt32(&c.crashed, 1)
+}
+
+func (c *fakeTelegramClient) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(&c.crashed) == 1 {
+		return nil, errors.New("process crashed")
+	}
+	body := `{"ok":true,"result":true}`
+	if apiMethod(req) == "getUpdates" {
+		var offset int64
+		fmt.Sscan(req.URL.Query().Get("offset"), &offset)
+		c.telegram.mu.Lock()
+		var results []string
+		var remaining []int64
+		for _, id := range c.telegram.updates {
+			if id < offset {
+				continue
+			}
+			remaining = append(remaining, id)
+			results = append(results, fmt.Sprintf(`{"update_id":%d,"message":{"message_id":%d,"chat":{"id":1}}}`, id, id))
+		}
+		c.telegram.updates = remaining
+		c.telegram.mu.Unlock()
+		if len(results) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		body = `{"ok":true,"result":[` + strings.Join(results, ",") + `]}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+type matchAll struct{}
+
+func (matchAll) Match(update Update) bool { return true }
+
+func TestCrashRedeliversInFlightUpdate(t *testing.T) {
+	telegram := &fakeTelegram{updates: []int64{1, 2, 3}}
+	store := &memoryOffsetStore{}
+	handled := make(chan int64, 10)
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	first := NewBot("1:test")
+	firstClient := &fakeTelegramClient{telegram: telegram}
+	first.HTTPClient = &http.Client{Transport: firstClient}
+	first.UseOffsetStore(store)
+	first.AddHandler(matchAll{}, func(update Update) {
+		if update.Update_id == 2 {
+			<-stuck
+			return
+		}
+		handled <- update.Update_id
+	})
+	first.Start()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-handled:
+		case <-time.After(2 * time.Second):
+			t.Fatal("first process did not handle updates 1 and 3")
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	firstClient.crash()
+
+	if offset, _ := store.LoadOffset(); offset != 2 {
+		t.Fatalf("saved offset = %d, want 2 while update 2 is in flight", offset)
+	}
+
+	second := NewBot("1:test")
+	second.HTTPClient = &http.Client{Transport: &fakeTelegramClient{telegram: telegram}}
+	second.UseOffsetStore(store)
+	second.AddHandler(matchAll{}, func(update Update) {
+		handled <- update.Update_id
+	})
+	second.Start()
+
+	// update 3 comes back too, since it was handled after the offset stopped
+	// at 2, but update 1 is below the saved offset and must not
+	deadline := time.After(2 * time.Second)
+	for redelivered := false; !redelivered; {
+		select {
+		case id := <-handled:
+			if id == 1 {
+				t.Fatal("update 1 was handled again after the restart")
+			}
+			redelivered = id == 2
+		case <-deadline:
+			t.Fatal("update in flight during the crash was not delivered again")
+		}
+	}
+}
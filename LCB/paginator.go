@@ -0,0 +1,121 @@
+package LCB
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type PageSource func(userID int64, offset int, limit int) ([]interface{}, int)
+
+type ItemRenderer func(item interface{}) InlineKeyboardButton
+
+type Paginator struct {
+	Name      string
+	PageSize  int
+	Columns   int
+	Source    PageSource
+	Render    ItemRenderer
+	Text      func(userID int64, page int, pages int) string
+	ParseMode string
+	PrevText  string
+	NextText  string
+	Footer    []InlineKeyboardButton
+	bot       *Bot
+}
+
+func (b *Bot) NewPaginator(name string, pageSize int, source PageSource, render ItemRenderer) *Paginator {
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	p := &Paginator{
+		Name:     name,
+		PageSize: pageSize,
+		Columns:  1,
+		Source:   source,
+		Render:   render,
+		PrevText: "◀",
+		NextText: "▶",
+		bot:      b,
+	}
+	b.AddHandler(FilterCallbackPrefix{Prefix: p.prefix()}, p.handle)
+	return p
+}
+
+func (p *Paginator) prefix() string {
+	return "pg:" + p.Name + ":"
+}
+
+func (p *Paginator) Keyboard(userID int64, page int) (*InlineKeyboardMarkup, int, int) {
+	if page < 0 {
+		page = 0
+	}
+	items, total := p.Source(userID, page*p.PageSize, p.PageSize)
+	pages := (total + p.PageSize - 1) / p.PageSize
+	if pages < 1 {
+		pages = 1
+	}
+	if page >= pages {
+		page = pages - 1
+		items, _ = p.Source(userID, page*p.PageSize, p.PageSize)
+	}
+
+	buttons := make([]InlineKeyboardButton, 0, len(items))
+	for _, item := range items {
+		buttons = append(buttons, p.Render(item))
+	}
+	keyboard := NewInline().Grid(buttons, p.Columns)
+
+	if pages > 1 {
+		nav := []InlineKeyboardButton{}
+		if page > 0 {
+			nav = append(nav, p.bot.CallbackBtn(p.PrevText, p.prefix()+strconv.Itoa(page-1)))
+		}
+		nav = append(nav, p.bot.CallbackBtn(fmt.Sprintf("%d/%d", page+1, pages), p.prefix()+"noop"))
+		if page < pages-1 {
+			nav = append(nav, p.bot.CallbackBtn(p.NextText, p.prefix()+strconv.Itoa(page+1)))
+		}
+		keyboard.Row(nav...)
+	}
+	keyboard.Row(p.Footer...)
+
+	return keyboard, page, pages
+}
+
+func (p *Paginator) text(userID int64, page int, pages int) string {
+	if p.Text == nil {
+		return fmt.Sprintf("Page %d/%d", page+1, pages)
+	}
+	return p.Text(userID, page, pages)
+}
+
+func (p *Paginator) Send(chatID int64, userID int64) int {
+	keyboard, page, pages := p.Keyboard(userID, 0)
+	return p.bot.SendMessage(chatID, p.text(userID, page, pages), p.ParseMode, keyboard.Keyboards())
+}
+
+func (p *Paginator) handle(update Update) {
+	query := update.CallbackQuery
+	if query.Message == nil || query.Message.Chat == nil || query.From == nil {
+		return
+	}
+
+	defer func() {
+		if err := p.bot.AnswerCallbackQuery(query.ID, "", false, "", 0); err != nil {
+			log.Println("Error answering callback:", err)
+		}
+	}()
+
+	data := strings.TrimPrefix(query.Data, p.prefix())
+	if data == "noop" {
+		return
+	}
+	page, err := strconv.Atoi(data)
+	if err != nil {
+		return
+	}
+
+	keyboard, page, pages := p.Keyboard(query.From.ID, page)
+	p.bot.EditMessage(query.Message.Chat.ID, query.Message.Message_id, p.text(query.From.ID, page, pages), p.ParseMode, keyboard.Keyboards())
+}
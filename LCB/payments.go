@@ -0,0 +1,257 @@
+package LCB
+
+const CurrencyStars = "XTR"
+
+type LabeledPrice struct {
+	Label  string `json:"label"`
+	Amount int    `json:"amount"`
+}
+
+type InvoiceParams struct {
+	Title                     string
+	Description               string
+	Payload                   string
+	ProviderToken             string
+	Currency                  string
+	Prices                    []LabeledPrice
+	SubscriptionPeriod        int
+	MaxTipAmount              int
+	SuggestedTipAmounts       []int
+	StartParameter            string
+	ProviderData              string
+	PhotoURL                  string
+	PhotoSize                 int
+	PhotoWidth                int
+	PhotoHeight               int
+	NeedName                  bool
+	NeedPhoneNumber           bool
+	NeedEmail                 bool
+	NeedShippingAddress       bool
+	SendPhoneNumberToProvider bool
+	SendEmailToProvider       bool
+	IsFlexible                bool
+}
+
+type ShippingAddress struct {
+	CountryCode string `json:"country_code"`
+	State       string `json:"state"`
+	City        string `json:"city"`
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2"`
+	PostCode    string `json:"post_code"`
+}
+
+type OrderInfo struct {
+	Name            string           `json:"name"`
+	PhoneNumber     string           `json:"phone_number"`
+	Email           string           `json:"email"`
+	ShippingAddress *ShippingAddress `json:"shipping_address"`
+}
+
+type ShippingOption struct {
+	ID     string         `json:"id"`
+	Title  string         `json:"title"`
+	Prices []LabeledPrice `json:"prices"`
+}
+
+type ShippingQuery struct {
+	ID              string           `json:"id"`
+	From            *User            `json:"from"`
+	InvoicePayload  string           `json:"invoice_payload"`
+	ShippingAddress *ShippingAddress `json:"shipping_address"`
+}
+
+type PreCheckoutQuery struct {
+	ID               string     `json:"id"`
+	From             *User      `json:"from"`
+	Currency         string     `json:"currency"`
+	TotalAmount      int        `json:"total_amount"`
+	InvoicePayload   string     `json:"invoice_payload"`
+	ShippingOptionID string     `json:"shipping_option_id"`
+	OrderInfo        *OrderInfo `json:"order_info"`
+}
+
+type SuccessfulPayment struct {
+	Currency                   string     `json:"currency"`
+	TotalAmount                int        `json:"total_amount"`
+	InvoicePayload             string     `json:"invoice_payload"`
+	SubscriptionExpirationDate int64      `json:"subscription_expiration_date"`
+	IsRecurring                bool       `json:"is_recurring"`
+	IsFirstRecurring           bool       `json:"is_first_recurring"`
+	ShippingOptionID           string     `json:"shipping_option_id"`
+	OrderInfo                  *OrderInfo `json:"order_info"`
+	TelegramPaymentChargeID    string     `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID    string     `json:"provider_payment_charge_id"`
+}
+
+type RefundedPayment struct {
+	Currency                string `json:"currency"`
+	TotalAmount             int    `json:"total_amount"`
+	InvoicePayload          string `json:"invoice_payload"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string `json:"provider_payment_charge_id"`
+}
+
+type FilterShippingQuery struct {
+	Payload string
+}
+
+type FilterPreCheckoutQuery struct {
+	Payload string
+}
+
+type FilterSuccessfulPayment struct {
+	Payload  string
+	Currency string
+}
+
+func (f FilterShippingQuery) Match(update Update) bool {
+	if update.ShippingQuery == nil {
+		return false
+	}
+	return f.Payload == "" || update.ShippingQuery.InvoicePayload == f.Payload
+}
+
+func (f FilterPreCheckoutQuery) Match(update Update) bool {
+	if update.PreCheckoutQuery == nil {
+		return false
+	}
+	return f.Payload == "" || update.PreCheckoutQuery.InvoicePayload == f.Payload
+}
+
+func (f FilterSuccessfulPayment) Match(update Update) bool {
+	if update.Message == nil || update.Message.SuccessfulPayment == nil {
+		return false
+	}
+	payment := update.Message.SuccessfulPayment
+	if f.Payload != "" && payment.InvoicePayload != f.Payload {
+		return false
+	}
+	return f.Currency == "" || payment.Currency == f.Currency
+}
+
+func (p InvoiceParams) apply(message map[string]interface{}) {
+	message["title"] = p.Title
+	message["description"] = p.Description
+	message["payload"] = p.Payload
+	message["currency"] = p.Currency
+	message["prices"] = p.Prices
+
+	if p.ProviderToken != "" {
+		message["provider_token"] = p.ProviderToken
+	}
+	if p.SubscriptionPeriod != 0 {
+		message["subscription_period"] = p.SubscriptionPeriod
+	}
+	if p.MaxTipAmount != 0 {
+		message["max_tip_amount"] = p.MaxTipAmount
+	}
+	if len(p.SuggestedTipAmounts) > 0 {
+		message["suggested_tip_amounts"] = p.SuggestedTipAmounts
+	}
+	if p.StartParameter != "" {
+		message["start_parameter"] = p.StartParameter
+	}
+	if p.ProviderData != "" {
+		message["provider_data"] = p.ProviderData
+	}
+	if p.PhotoURL != "" {
+		message["photo_url"] = p.PhotoURL
+	}
+	if p.PhotoSize != 0 {
+		message["photo_size"] = p.PhotoSize
+	}
+	if p.PhotoWidth != 0 {
+		message["photo_width"] = p.PhotoWidth
+	}
+	if p.PhotoHeight != 0 {
+		message["photo_height"] = p.PhotoHeight
+	}
+
+	flags := map[string]bool{
+		"need_name":                     p.NeedName,
+		"need_phone_number":             p.NeedPhoneNumber,
+		"need_email":                    p.NeedEmail,
+		"need_shipping_address":         p.NeedShippingAddress,
+		"send_phone_number_to_provider": p.SendPhoneNumberToProvider,
+		"send_email_to_provider":        p.SendEmailToProvider,
+		"is_flexible":                   p.IsFlexible,
+	}
+	for key, value := range flags {
+		if value {
+			message[key] = true
+		}
+	}
+}
+
+func (b *Bot) SendInvoice(chatID int64, invoice InvoiceParams, keyboards *Keyboards, opts ...SendOptions) (int, error) {
+	message := map[string]interface{}{
+		"chat_id": chatID,
+	}
+
+	invoice.apply(message)
+	sendOptions(opts).apply(message, sendInvoiceOptions)
+
+	markup, err := keyboards.InlineMarkup()
+	if err != nil {
+		return 0, err
+	}
+	if markup != nil {
+		message["reply_markup"] = markup
+	}
+
+	var result MessageID
+	if err := b.callMethod("sendInvoice", message, &result); err != nil {
+		return 0, err
+	}
+	return result.MessageID, nil
+}
+
+func (b *Bot) CreateInvoiceLink(invoice InvoiceParams) (string, error) {
+	message := map[string]interface{}{}
+
+	invoice.apply(message)
+
+	var result string
+	if err := b.callMethod("createInvoiceLink", message, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func (b *Bot) AnswerShippingQuery(shippingQueryID string, ok bool, options []ShippingOption, errorMessage string) error {
+	message := map[string]interface{}{
+		"shipping_query_id": shippingQueryID,
+		"ok":                ok,
+	}
+
+	if ok {
+		message["shipping_options"] = options
+	} else {
+		message["error_message"] = errorMessage
+	}
+
+	return b.callMethod("answerShippingQuery", message, nil)
+}
+
+func (b *Bot) AnswerPreCheckoutQuery(preCheckoutQueryID string, ok bool, errorMessage string) error {
+	message := map[string]interface{}{
+		"pre_checkout_query_id": preCheckoutQueryID,
+		"ok":                    ok,
+	}
+
+	if !ok {
+		message["error_message"] = errorMessage
+	}
+
+	return b.callMethod("answerPreCheckoutQuery", message, nil)
+}
+
+func (b *Bot) RefundStarPayment(userID int64, telegramPaymentChargeID string) error {
+	message := map[string]interface{}{
+		"user_id":                    userID,
+		"telegram_payment_charge_id": telegramPaymentChargeID,
+	}
+
+	return b.callMethod("refundStarPayment", message, nil)
+}
@@ -0,0 +1,191 @@
+package LCB
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+const throttleSweepInterval = time.Minute
+
+const (
+	ThrottleDrop   = "drop"
+	ThrottleQueue  = "queue"
+	ThrottleNotify = "notify"
+)
+
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type ThrottleConfig struct {
+	PerUser      RateLimit
+	PerChat      RateLimit
+	Policy       string
+	MaxWait      time.Duration
+	CooldownText string
+}
+
+type Throttler struct {
+	config   ThrottleConfig
+	bot      *Bot
+	mu       sync.Mutex
+	users    map[int64]*tokenBucket
+	chats    map[int64]*tokenBucket
+	notified map[int64]time.Time
+	swept    time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewThrottler(config ThrottleConfig) *Throttler {
+	if config.Policy == "" {
+		config.Policy = ThrottleDrop
+	}
+	if config.MaxWait <= 0 {
+		config.MaxWait = 10 * time.Second
+	}
+	if config.CooldownText == "" {
+		config.CooldownText = "Too many requests, please slow down."
+	}
+	return &Throttler{
+		config:   config,
+		users:    make(map[int64]*tokenBucket),
+		chats:    make(map[int64]*tokenBucket),
+		notified: make(map[int64]time.Time),
+	}
+}
+
+func (b *Bot) UseThrottler(t *Throttler) {
+	t.bot = b
+	b.throttler = t
+}
+
+func (b *Bot) Throttle(t *Throttler, callback func(update Update)) func(update Update) {
+	if t.bot == nil {
+		t.bot = b
+	}
+	return func(update Update) {
+		wait, ok := t.admit(update)
+		if !ok {
+			return
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+		callback(update)
+	}
+}
+
+// admit never throttles payment queries: Telegram cancels the payment unless
+// they are answered within 10 seconds.
+func (t *Throttler) admit(update Update) (time.Duration, bool) {
+	if update.PreCheckoutQuery != nil || update.ShippingQuery != nil {
+		return 0, true
+	}
+	userID, chatID := updateUserID(update), updateChatID(update)
+	now := time.Now()
+
+	t.mu.Lock()
+	t.sweep(now)
+	userBucket := t.bucket(t.users, userID, t.config.PerUser, now)
+	chatBucket := t.bucket(t.chats, chatID, t.config.PerChat, now)
+
+	wait := math.Max(userBucket.wait(t.config.PerUser), chatBucket.wait(t.config.PerChat))
+	delay := time.Duration(wait * float64(time.Second))
+	if delay > 0 && (t.config.Policy != ThrottleQueue || delay > t.config.MaxWait) {
+		query := update.CallbackQuery
+		notify := t.config.Policy == ThrottleNotify && query == nil && chatID != 0 && now.After(t.notified[userID])
+		if notify {
+			t.notified[userID] = now.Add(delay)
+		}
+		t.mu.Unlock()
+
+		if t.bot == nil {
+			return 0, false
+		}
+		// a dropped callback is answered so the client stops its spinner
+		if query != nil {
+			text := ""
+			if t.config.Policy == ThrottleNotify {
+				text = t.config.CooldownText
+			}
+			go func() {
+				if err := t.bot.AnswerCallbackQuery(query.ID, text, false, "", 0); err != nil {
+					log.Println("Error answering throttled callback:", err)
+				}
+			}()
+		} else if notify {
+			go t.bot.SendMessage(chatID, t.config.CooldownText, "", nil)
+		}
+		return 0, false
+	}
+
+	userBucket.take()
+	chatBucket.take()
+	t.mu.Unlock()
+	return delay, true
+}
+
+// sweep drops buckets that have refilled to full, since a fresh bucket would
+// behave the same, and cooldown notices that have expired.
+func (t *Throttler) sweep(now time.Time) {
+	if now.Sub(t.swept) < throttleSweepInterval {
+		return
+	}
+	t.swept = now
+	sweepBuckets(t.users, t.config.PerUser, now)
+	sweepBuckets(t.chats, t.config.PerChat, now)
+	for userID, until := range t.notified {
+		if now.After(until) {
+			delete(t.notified, userID)
+		}
+	}
+}
+
+func sweepBuckets(buckets map[int64]*tokenBucket, limit RateLimit, now time.Time) {
+	for id, bucket := range buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate >= float64(limit.burst()) {
+			delete(buckets, id)
+		}
+	}
+}
+
+func (t *Throttler) bucket(buckets map[int64]*tokenBucket, id int64, limit RateLimit, now time.Time) *tokenBucket {
+	if limit.Rate <= 0 || id == 0 {
+		return nil
+	}
+	bucket, ok := buckets[id]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.burst()), last: now}
+		buckets[id] = bucket
+	}
+	bucket.tokens = math.Min(float64(limit.burst()), bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+	bucket.last = now
+	return bucket
+}
+
+func (l RateLimit) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+func (tb *tokenBucket) wait(limit RateLimit) float64 {
+	if tb == nil || tb.tokens >= 1 {
+		return 0
+	}
+	return (1 - tb.tokens) / limit.Rate
+}
+
+func (tb *tokenBucket) take() {
+	if tb != nil {
+		tb.tokens--
+	}
+}
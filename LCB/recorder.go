@@ -0,0 +1,288 @@
+package LCB
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RecordUpdate = "update"
+	RecordCall   = "call"
+)
+
+type RecordEntry struct {
+	Time     time.Time       `json:"time"`
+	Kind     string          `json:"kind"`
+	Update   json.RawMessage `json:"update,omitempty"`
+	Method   string          `json:"method,omitempty"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Recorder appends raw updates and outgoing API calls to a JSONL file. The
+// bot token is never written, only the method name.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{file: file}, nil
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recorder) write(entry RecordEntry) {
+	entry.Time = time.Now()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		log.Println("Error writing recording:", err)
+	}
+}
+
+func (r *Recorder) recordUpdates(body []byte) {
+	var response struct {
+		Result []json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return
+	}
+	for _, update := range response.Result {
+		r.write(RecordEntry{Kind: RecordUpdate, Update: update})
+	}
+}
+
+func (b *Bot) UseRecorder(recorder *Recorder) {
+	client := http.Client{}
+	if b.HTTPClient != nil {
+		client = *b.HTTPClient
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &recordingTransport{base: base, recorder: recorder}
+	b.HTTPClient = &client
+	b.recorder = recorder
+}
+
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := apiMethod(req)
+	if method == "" || method == "getUpdates" {
+		return t.base.RoundTrip(req)
+	}
+
+	request, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := RecordEntry{Kind: RecordCall, Method: method, Request: request}
+	if json.Valid(body) {
+		entry.Response = body
+	}
+	t.recorder.write(entry)
+	return resp, nil
+}
+
+// apiMethod returns the Bot API method of a request, or "" for file downloads.
+func apiMethod(req *http.Request) string {
+	path := req.URL.Path
+	if !strings.HasPrefix(path, "/bot") {
+		return ""
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// readRequestBody returns the JSON body of a request and puts it back for
+// sending. Multipart uploads are recorded without the file contents.
+func readRequestBody(req *http.Request) (json.RawMessage, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return json.RawMessage(`"multipart"`), nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if !json.Valid(body) {
+		return nil, nil
+	}
+	return body, nil
+}
+
+func ReadRecording(path string) ([]RecordEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []RecordEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry RecordEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Replay feeds recorded updates through the handlers without talking to
+// Telegram. Outgoing calls are captured in Calls and answered with the
+// recorded response for the same method, in order, when there is one.
+type Replay struct {
+	mu        sync.Mutex
+	Calls     []RecordEntry
+	responses map[string][]json.RawMessage
+}
+
+func (r *Replay) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := apiMethod(req)
+	request, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	response := replayFallback(method)
+	if recorded := r.responses[method]; len(recorded) > 0 {
+		response = recorded[0]
+		r.responses[method] = recorded[1:]
+	}
+	r.Calls = append(r.Calls, RecordEntry{Time: time.Now(), Kind: RecordCall, Method: method, Request: request, Response: response})
+	r.mu.Unlock()
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(response)),
+		Request:    req,
+	}, nil
+}
+
+func (r *Replay) CallsTo(method string) []RecordEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []RecordEntry
+	for _, call := range r.Calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func replayFallback(method string) json.RawMessage {
+	for _, prefix := range []string{"send", "edit", "copyMessage", "forwardMessage"} {
+		if strings.HasPrefix(method, prefix) {
+			return json.RawMessage(`{"ok":true,"result":{"message_id":1}}`)
+		}
+	}
+	return json.RawMessage(`{"ok":true,"result":true}`)
+}
+
+// Replay runs the updates recorded in path through the bot's handlers and
+// waits up to timeout for them to finish. It needs a dedicated bot that has
+// not been started. The bot stays in replay mode afterwards: timers and
+// goroutines the handlers left behind keep talking to the replay transport,
+// never to Telegram. Replay never saves the update offset and skips the
+// throttler; callback data the codec cannot decode reaches the handlers as
+// recorded instead of being dropped.
+func (b *Bot) Replay(path string, timeout time.Duration) (*Replay, error) {
+	if b.updates != nil && !b.replaying {
+		return nil, fmt.Errorf("replay: bot is already running")
+	}
+	entries, err := ReadRecording(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &Replay{responses: make(map[string][]json.RawMessage)}
+	var updates []Update
+	for _, entry := range entries {
+		switch entry.Kind {
+		case RecordUpdate:
+			var update Update
+			if err := json.Unmarshal(entry.Update, &update); err != nil {
+				return nil, err
+			}
+			updates = append(updates, update)
+		case RecordCall:
+			if entry.Response != nil {
+				replay.responses[entry.Method] = append(replay.responses[entry.Method], entry.Response)
+			}
+		}
+	}
+
+	b.replaying = true
+	b.HTTPClient = &http.Client{Transport: replay}
+	b.recorder = nil
+
+	b.updates = newUpdateTracker(0, len(updates)+1)
+	updatesChan := make(chan Update)
+	go b.processUpdates(updatesChan)
+	for _, update := range updates {
+		if b.updates.dispatch(update.Update_id) {
+			updatesChan <- update
+		}
+	}
+	close(updatesChan)
+
+	deadline := time.Now().Add(timeout)
+	for !b.updates.idle() {
+		if time.Now().After(deadline) {
+			return replay, fmt.Errorf("replay: handlers still running after %s", timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return replay, nil
+}
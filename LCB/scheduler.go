@@ -0,0 +1,404 @@
+package LCB
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrSchedulerRunning = errors.New("scheduler is already running")
+)
+
+type ScheduledJob struct {
+	ID        string            `json:"id"`
+	Cron      string            `json:"cron,omitempty"`
+	NextRun   time.Time         `json:"next_run"`
+	ChatID    int64             `json:"chat_id,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	ParseMode string            `json:"parse_mode,omitempty"`
+	Handler   string            `json:"handler,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+type JobStore interface {
+	LoadJobs() ([]ScheduledJob, error)
+	SaveJob(job ScheduledJob) error
+	DeleteJob(id string) error
+}
+
+type Scheduler struct {
+	bot      *Bot
+	store    JobStore
+	Location *time.Location
+	mu       sync.Mutex
+	jobs     map[string]*ScheduledJob
+	crons    map[string]*CronSchedule
+	handlers map[string]func(job ScheduledJob)
+	wake     chan struct{}
+	stop     chan struct{}
+}
+
+func (b *Bot) NewScheduler(store JobStore) *Scheduler {
+	if store == nil {
+		store = NewMemoryJobStore()
+	}
+	return &Scheduler{
+		bot:      b,
+		store:    store,
+		Location: time.Local,
+		jobs:     make(map[string]*ScheduledJob),
+		crons:    make(map[string]*CronSchedule),
+		handlers: make(map[string]func(job ScheduledJob)),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Handle registers a named handler for jobs with Handler set. Handlers are
+// referenced by name so that jobs can be stored and reloaded after a restart.
+func (s *Scheduler) Handle(name string, handler func(job ScheduledJob)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[name] = handler
+}
+
+func (s *Scheduler) Schedule(job ScheduledJob) (string, error) {
+	if job.ID == "" {
+		job.ID = newJobID()
+	}
+	if job.Handler == "" && job.ChatID == 0 {
+		return "", fmt.Errorf("job %s: either Handler or ChatID must be set", job.ID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job.Cron != "" {
+		schedule, err := ParseCron(job.Cron)
+		if err != nil {
+			return "", err
+		}
+		if job.NextRun.IsZero() {
+			job.NextRun = schedule.Next(time.Now().In(s.Location))
+		}
+		s.crons[job.ID] = schedule
+	} else if job.NextRun.IsZero() {
+		return "", fmt.Errorf("job %s: NextRun or Cron must be set", job.ID)
+	} else {
+		delete(s.crons, job.ID)
+	}
+
+	if err := s.store.SaveJob(job); err != nil {
+		return "", err
+	}
+	s.jobs[job.ID] = &job
+	s.notify()
+	return job.ID, nil
+}
+
+func (s *Scheduler) SendAt(at time.Time, chatID int64, text string, parseMode string) (string, error) {
+	return s.Schedule(ScheduledJob{NextRun: at, ChatID: chatID, Text: text, ParseMode: parseMode})
+}
+
+func (s *Scheduler) SendAfter(delay time.Duration, chatID int64, text string, parseMode string) (string, error) {
+	return s.SendAt(time.Now().Add(delay), chatID, text, parseMode)
+}
+
+func (s *Scheduler) RunAt(at time.Time, handler string, data map[string]string) (string, error) {
+	return s.Schedule(ScheduledJob{NextRun: at, Handler: handler, Data: data})
+}
+
+func (s *Scheduler) Every(spec string, handler string, data map[string]string) (string, error) {
+	return s.Schedule(ScheduledJob{Cron: spec, Handler: handler, Data: data})
+}
+
+func (s *Scheduler) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrJobNotFound
+	}
+	delete(s.jobs, id)
+	delete(s.crons, id)
+	s.notify()
+	return s.store.DeleteJob(id)
+}
+
+func (s *Scheduler) Jobs() []ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].NextRun.Before(jobs[j].NextRun) })
+	return jobs
+}
+
+// Start loads stored jobs and runs them in the background. One-off jobs that
+// became due while the bot was down run immediately; recurring jobs skip
+// missed runs. One-off jobs stay in the store until they have run, so a job
+// interrupted by a crash runs again on the next start.
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return ErrSchedulerRunning
+	}
+
+	stored, err := s.store.LoadJobs()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(s.Location)
+	for i := range stored {
+		job := stored[i]
+		if job.Cron != "" {
+			schedule, err := ParseCron(job.Cron)
+			if err != nil {
+				log.Println("Dropping scheduled job", job.ID+":", err)
+				continue
+			}
+			s.crons[job.ID] = schedule
+			if job.NextRun.Before(now) {
+				job.NextRun = schedule.Next(now)
+			}
+		}
+		s.jobs[job.ID] = &job
+	}
+	s.stop = make(chan struct{})
+	go s.loop(s.stop)
+	return nil
+}
+
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) loop(stop chan struct{}) {
+	for {
+		wait := s.runDue()
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// runDue starts all due jobs and returns the time until the next one.
+func (s *Scheduler) runDue() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().In(s.Location)
+	wait := time.Hour
+	for id, job := range s.jobs {
+		if job.NextRun.After(now) {
+			if until := job.NextRun.Sub(now); until < wait {
+				wait = until
+			}
+			continue
+		}
+
+		due := *job
+		if schedule, ok := s.crons[id]; ok {
+			job.NextRun = schedule.Next(now)
+			if job.NextRun.IsZero() {
+				delete(s.jobs, id)
+				delete(s.crons, id)
+				if err := s.store.DeleteJob(id); err != nil {
+					log.Println("Error deleting scheduled job:", err)
+				}
+			} else {
+				if err := s.store.SaveJob(*job); err != nil {
+					log.Println("Error saving scheduled job:", err)
+				}
+				if until := job.NextRun.Sub(now); until < wait {
+					wait = until
+				}
+			}
+		} else {
+			delete(s.jobs, id)
+		}
+		go s.run(due, s.handlers[due.Handler])
+	}
+	return wait
+}
+
+func (s *Scheduler) run(job ScheduledJob, handler func(job ScheduledJob)) {
+	if job.Cron == "" {
+		defer s.finish(job.ID)
+	}
+	if job.Handler != "" {
+		if handler == nil {
+			log.Println("No handler registered for scheduled job", job.ID+":", job.Handler)
+			return
+		}
+		handler(job)
+		return
+	}
+	if _, err := s.bot.TrySendMessage(job.ChatID, job.Text, job.ParseMode, nil); err != nil {
+		log.Println("Error sending scheduled message:", err)
+	}
+}
+
+// finish removes a one-off job from the store once it has run, unless it was
+// scheduled again under the same ID in the meantime.
+func (s *Scheduler) finish(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; ok {
+		return
+	}
+	if err := s.store.DeleteJob(id); err != nil {
+		log.Println("Error deleting scheduled job:", err)
+	}
+}
+
+func newJobID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+type MemoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]ScheduledJob
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]ScheduledJob)}
+}
+
+func (s *MemoryJobStore) LoadJobs() ([]ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (s *MemoryJobStore) SaveJob(job ScheduledJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *MemoryJobStore) DeleteJob(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+// FileJobStore keeps all jobs in a single JSON file, rewritten on every change.
+type FileJobStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileJobStore(path string) *FileJobStore {
+	return &FileJobStore{Path: path}
+}
+
+func (s *FileJobStore) read() (map[string]ScheduledJob, error) {
+	jobs := make(map[string]ScheduledJob)
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return jobs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return jobs, nil
+	}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("job store %s: %w", s.Path, err)
+	}
+	return jobs, nil
+}
+
+func (s *FileJobStore) write(jobs map[string]ScheduledJob) error {
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+func (s *FileJobStore) LoadJobs() ([]ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]ScheduledJob, 0, len(stored))
+	for _, job := range stored {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (s *FileJobStore) SaveJob(job ScheduledJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs, err := s.read()
+	if err != nil {
+		return err
+	}
+	jobs[job.ID] = job
+	return s.write(jobs)
+}
+
+func (s *FileJobStore) DeleteJob(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := jobs[id]; !ok {
+		return nil
+	}
+	delete(jobs, id)
+	return s.write(jobs)
+}
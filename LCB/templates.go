@@ -0,0 +1,319 @@
+package LCB
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+type MessageTemplate struct {
+	ParseMode string            `json:"parse_mode"`
+	Text      string            `json:"text"`
+	Keyboard  *TemplateKeyboard `json:"keyboard,omitempty"`
+
+	compiled *template.Template
+}
+
+type TemplateKeyboard struct {
+	Inline  [][]TemplateButton `json:"inline,omitempty"`
+	Reply   [][]TemplateButton `json:"reply,omitempty"`
+	Resize  bool               `json:"resize,omitempty"`
+	OneTime bool               `json:"one_time,omitempty"`
+	Remove  bool               `json:"remove,omitempty"`
+}
+
+type TemplateButton struct {
+	Text     string `json:"text"`
+	Callback string `json:"callback,omitempty"`
+	URL      string `json:"url,omitempty"`
+	WebApp   string `json:"web_app,omitempty"`
+	Pay      bool   `json:"pay,omitempty"`
+}
+
+type RenderedMessage struct {
+	Text      string
+	ParseMode string
+	Keyboards *Keyboards
+}
+
+type TemplateRegistry struct {
+	DefaultLocale string
+	mu            sync.RWMutex
+	funcs         template.FuncMap
+	templates     map[string]map[string]*MessageTemplate
+}
+
+func NewTemplateRegistry(defaultLocale string) *TemplateRegistry {
+	return &TemplateRegistry{
+		DefaultLocale: defaultLocale,
+		funcs: template.FuncMap{
+			"html": EscapeHTML,
+			"md":   EscapeMarkdownV2,
+			"code": escapeMarkdownV2Code,
+		},
+		templates: make(map[string]map[string]*MessageTemplate),
+	}
+}
+
+// Funcs must be called before templates using them are added.
+func (r *TemplateRegistry) Funcs(funcs template.FuncMap) *TemplateRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, fn := range funcs {
+		r.funcs[name] = fn
+	}
+	return r
+}
+
+func (r *TemplateRegistry) Add(name string, locale string, tmpl MessageTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	compiled, err := compileTemplate(name, &tmpl, r.funcs)
+	if err != nil {
+		return err
+	}
+	tmpl.compiled = compiled
+
+	if r.templates[name] == nil {
+		r.templates[name] = make(map[string]*MessageTemplate)
+	}
+	r.templates[name][normalizeLocale(locale)] = &tmpl
+	return nil
+}
+
+// LoadData decodes a template definition with any unmarshal function, e.g. yaml.Unmarshal.
+func (r *TemplateRegistry) LoadData(name string, locale string, data []byte, unmarshal func([]byte, interface{}) error) error {
+	var raw map[string]interface{}
+	if err := unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	var tmpl MessageTemplate
+	if err := json.Unmarshal(normalized, &tmpl); err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	return r.Add(name, locale, tmpl)
+}
+
+// LoadFile reads name.json or name.locale.json.
+func (r *TemplateRegistry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	locale := ""
+	if i := strings.Index(name, "."); i >= 0 {
+		name, locale = name[:i], name[i+1:]
+	}
+	return r.LoadData(name, locale, data, json.Unmarshal)
+}
+
+func (r *TemplateRegistry) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := r.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TemplateRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.templates[name]) > 0
+}
+
+func (r *TemplateRegistry) lookup(name string, locale string) (*MessageTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	variants := r.templates[name]
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+	candidates := []string{normalizeLocale(locale), baseLanguage(locale), "", normalizeLocale(r.DefaultLocale)}
+	for _, candidate := range candidates {
+		if tmpl, ok := variants[candidate]; ok {
+			return tmpl, nil
+		}
+	}
+	return nil, fmt.Errorf("template %s has no variant for locale %q", name, locale)
+}
+
+func (r *TemplateRegistry) Render(name string, locale string, data interface{}) (*RenderedMessage, error) {
+	tmpl, err := r.lookup(name, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := executeTemplate(tmpl.compiled, "text", data)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+
+	rendered := &RenderedMessage{Text: text, ParseMode: tmpl.ParseMode}
+	if tmpl.Keyboard != nil {
+		rendered.Keyboards, err = renderTemplateKeyboard(tmpl.compiled, tmpl.Keyboard, data)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+	}
+	return rendered, nil
+}
+
+func compileTemplate(name string, tmpl *MessageTemplate, funcs template.FuncMap) (*template.Template, error) {
+	root := template.New(name).Funcs(funcs).Option("missingkey=error")
+	if _, err := root.New("text").Parse(tmpl.Text); err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	if tmpl.Keyboard == nil {
+		return root, nil
+	}
+	rows := map[string][][]TemplateButton{"inline": tmpl.Keyboard.Inline, "reply": tmpl.Keyboard.Reply}
+	for kind, buttons := range rows {
+		for i, row := range buttons {
+			for j, button := range row {
+				fields := map[string]string{"text": button.Text, "callback": button.Callback, "url": button.URL, "web_app": button.WebApp}
+				for field, source := range fields {
+					if source == "" {
+						continue
+					}
+					if _, err := root.New(buttonTemplateName(kind, i, j, field)).Parse(source); err != nil {
+						return nil, fmt.Errorf("template %s: %w", name, err)
+					}
+				}
+			}
+		}
+	}
+	return root, nil
+}
+
+func buttonTemplateName(kind string, row int, column int, field string) string {
+	return fmt.Sprintf("%s.%d.%d.%s", kind, row, column, field)
+}
+
+func executeTemplate(root *template.Template, name string, data interface{}) (string, error) {
+	if root.Lookup(name) == nil {
+		return "", nil
+	}
+	var buffer bytes.Buffer
+	if err := root.ExecuteTemplate(&buffer, name, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func renderTemplateKeyboard(root *template.Template, keyboard *TemplateKeyboard, data interface{}) (*Keyboards, error) {
+	render := func(kind string, i int, j int, field string) (string, error) {
+		return executeTemplate(root, buttonTemplateName(kind, i, j, field), data)
+	}
+
+	switch {
+	case keyboard.Remove:
+		return NewDeleteKeyboard().Keyboards(), nil
+	case len(keyboard.Inline) > 0:
+		markup := NewInline()
+		for i, row := range keyboard.Inline {
+			buttons := make([]InlineKeyboardButton, 0, len(row))
+			for j, button := range row {
+				var rendered InlineKeyboardButton
+				var err error
+				if rendered.Text, err = render("inline", i, j, "text"); err != nil {
+					return nil, err
+				}
+				if rendered.CallbackData, err = render("inline", i, j, "callback"); err != nil {
+					return nil, err
+				}
+				if rendered.URL, err = render("inline", i, j, "url"); err != nil {
+					return nil, err
+				}
+				webApp, err := render("inline", i, j, "web_app")
+				if err != nil {
+					return nil, err
+				}
+				if webApp != "" {
+					rendered.WebApp = &WebAppInfo{URL: webApp}
+				}
+				rendered.Pay = button.Pay
+				buttons = append(buttons, rendered)
+			}
+			markup.Row(buttons...)
+		}
+		return markup.Keyboards(), nil
+	case len(keyboard.Reply) > 0:
+		markup := NewReply()
+		for i, row := range keyboard.Reply {
+			buttons := make([]ReplyKeyboardButton, 0, len(row))
+			for j := range row {
+				text, err := render("reply", i, j, "text")
+				if err != nil {
+					return nil, err
+				}
+				buttons = append(buttons, ReplyBtn(text))
+			}
+			markup.Row(buttons...)
+		}
+		if keyboard.Resize {
+			markup.Resize()
+		}
+		if keyboard.OneTime {
+			markup.OneTime()
+		}
+		return markup.Keyboards(), nil
+	}
+	return nil, nil
+}
+
+func (b *Bot) UseTemplates(registry *TemplateRegistry) {
+	b.templates = registry
+}
+
+func (b *Bot) RenderTemplate(name string, locale string, data interface{}) (*RenderedMessage, error) {
+	if b.templates == nil {
+		return nil, fmt.Errorf("no template registry, call UseTemplates first")
+	}
+	rendered, err := b.templates.Render(name, locale, data)
+	if err != nil {
+		return nil, err
+	}
+	if rendered.Keyboards != nil && rendered.Keyboards.Inline != nil {
+		for _, row := range rendered.Keyboards.Inline.InlineKeyboard {
+			for i := range row {
+				if row[i].CallbackData != "" {
+					data, err := b.TryCallbackData(row[i].CallbackData)
+					if err != nil {
+						return nil, fmt.Errorf("template %s: %w", name, err)
+					}
+					row[i].CallbackData = data
+				}
+			}
+		}
+	}
+	return rendered, nil
+}
+
+func (b *Bot) SendTemplate(chatID int64, name string, locale string, data interface{}, opts ...SendOptions) (int, error) {
+	rendered, err := b.RenderTemplate(name, locale, data)
+	if err != nil {
+		return 0, err
+	}
+	return b.TrySendMessage(chatID, rendered.Text, rendered.ParseMode, rendered.Keyboards, opts...)
+}
+
+func (b *Bot) ReplyTemplate(update Update, name string, data interface{}, opts ...SendOptions) (int, error) {
+	return b.SendTemplate(updateChatID(update), name, b.Locale(update), data, opts...)
+}